@@ -0,0 +1,123 @@
+package gluey
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Answers supplies the values for prompts when a context is not interactive.
+// Answers are looked up by the label of the prompt.
+type Answers interface {
+	Answer(label string) (string, bool)
+}
+
+// AnswerMap answers prompts from a map keyed by the prompt label. Multiple
+// selects are answered with a comma separated list, commas within a choice are
+// escaped with a backslash, which AnswerList will do.
+type AnswerMap map[string]string
+
+// AnswerEnv answers prompts from environment variables. The label is upper
+// cased, non alphanumeric characters are replaced with underscores and then it
+// is prefixed with the value of AnswerEnv. For example with AnswerEnv("APP_")
+// the label "Deploy target?" will read APP_DEPLOY_TARGET
+type AnswerEnv string
+
+// AnswerError is returned by a prompt in a non-interactive context when there
+// is no answer for it, or the answer is not valid for the prompt. If the answer
+// failed validation, Err is the validation error.
+type AnswerError struct {
+	Label  string
+	Answer string
+	Err    error
+}
+
+type answerChain []Answers
+
+// Answer looks up the label in the map
+func (am AnswerMap) Answer(label string) (string, bool) {
+	ans, ok := am[label]
+	return ans, ok
+}
+
+// Answer looks up the environment variable for the label
+func (ae AnswerEnv) Answer(label string) (string, bool) {
+	return os.LookupEnv(ae.Key(label))
+}
+
+// Key returns the environment variable name that will be read for the label
+func (ae AnswerEnv) Key(label string) string {
+	key := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, strings.TrimSpace(label))
+	return string(ae) + strings.Trim(key, "_")
+}
+
+func (ac answerChain) Answer(label string) (string, bool) {
+	for _, answers := range ac {
+		if ans, ok := answers.Answer(label); ok && ans != "" {
+			return ans, true
+		}
+	}
+	return "", false
+}
+
+// AnswerList joins the choices for a multiple select into a single answer,
+// escaping commas and backslashes within them.
+func AnswerList(choices ...string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, ",", `\,`)
+	escaped := make([]string, len(choices))
+	for i, choice := range choices {
+		escaped[i] = escaper.Replace(choice)
+	}
+	return strings.Join(escaped, ",")
+}
+
+// splitAnswer splits the answer for a multiple select on the commas that are
+// not escaped with a backslash, unescaping the choices.
+func splitAnswer(answer string) []string {
+	choices := []string{}
+	var choice strings.Builder
+	for i := 0; i < len(answer); i++ {
+		switch {
+		case answer[i] == '\\' && i+1 < len(answer):
+			i++
+			choice.WriteByte(answer[i])
+		case answer[i] == ',':
+			choices = append(choices, choice.String())
+			choice.Reset()
+		default:
+			choice.WriteByte(answer[i])
+		}
+	}
+	return append(choices, choice.String())
+}
+
+func (err *AnswerError) Error() string {
+	if err.Answer == "" {
+		return fmt.Sprintf("no answer for %q in non-interactive mode", err.Label)
+	} else if err.Err != nil {
+		return fmt.Sprintf("invalid answer %q for %q in non-interactive mode: %v", err.Answer, err.Label, err.Err)
+	}
+	return fmt.Sprintf("invalid answer %q for %q in non-interactive mode", err.Answer, err.Label)
+}
+
+// Unwrap returns the validation error if there was one
+func (err *AnswerError) Unwrap() error {
+	return err.Err
+}
+
+// answer resolves the answer for a label, falling back to dflt. If neither is
+// available an *AnswerError is returned.
+func (ctx *Ctx) answer(label, dflt string) (string, error) {
+	if ans, ok := ctx.answers.Answer(label); ok {
+		return ans, nil
+	} else if dflt != "" {
+		return dflt, nil
+	}
+	return "", &AnswerError{Label: label}
+}
@@ -0,0 +1,56 @@
+package gluey_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestNonInteractiveAsk(t *testing.T) {
+	term := gluetest.NewTerminal()
+	ctx := term.Ctx(gluey.NonInteractive(gluey.AnswerMap{"Name?": "gluey"}))
+	if input, err := ctx.Ask("Name?"); err != nil || input != "gluey" {
+		t.Errorf("Ask = %q, %v", input, err)
+	}
+	if input, err := ctx.AskDefault("Other?", "dflt"); err != nil || input != "dflt" {
+		t.Errorf("AskDefault = %q, %v", input, err)
+	}
+	var answerErr *gluey.AnswerError
+	if _, err := ctx.Ask("Missing?"); !errors.As(err, &answerErr) || answerErr.Label != "Missing?" {
+		t.Errorf("Ask without an answer = %v", err)
+	}
+}
+
+func TestAnswerEnv(t *testing.T) {
+	t.Setenv("APP_DEPLOY_TARGET", "prod")
+	if key := gluey.AnswerEnv("APP_").Key("Deploy target?"); key != "APP_DEPLOY_TARGET" {
+		t.Errorf("Key = %q", key)
+	}
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerEnv("APP_")))
+	if input, err := ctx.Ask("Deploy target?"); err != nil || input != "prod" {
+		t.Errorf("Ask = %q, %v", input, err)
+	}
+}
+
+func TestNonInteractiveSelect(t *testing.T) {
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"By label": "b", "By number": "3"}))
+	if _, item, err := ctx.Select("By label", []string{"a", "b", "c"}); err != nil || item != "b" {
+		t.Errorf("Select by label = %q, %v", item, err)
+	}
+	if _, item, err := ctx.Select("By number", []string{"a", "b", "c"}); err != nil || item != "c" {
+		t.Errorf("Select by number = %q, %v", item, err)
+	}
+}
+
+func TestNonInteractiveSelectMultiple(t *testing.T) {
+	items := []string{"a, b", `c\d`, "e"}
+	for _, answer := range []string{gluey.AnswerList("a, b", `c\d`), `a\, b,c\\d`, `1,2`} {
+		ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Pick": answer}))
+		if _, chosen, err := ctx.SelectMultiple("Pick", items); err != nil || !slices.Equal(chosen, items[:2]) {
+			t.Errorf("SelectMultiple(%q) = %q, %v", answer, chosen, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package gluey
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+	"github.com/tanema/gluey/term"
+)
+
+const anyKeyTemplate = `{{.Prefix}}
+{{- if .Cancelled -}}
+	{{ iconBad }} {{ .Label }} {{ "(cancelled)" | faint }}
+{{- else if .TimedOut -}}
+	{{ .Label }} {{ "(timed out)" | faint }}
+{{- else -}}
+	{{ .Label }}{{ with .Countdown }} {{ . | faint }}{{ end }}
+{{- end }}`
+
+// anyKeySequences are the escape sequences of keys that readline handles itself,
+// so they are not translated for it, named for AnyKey.
+var anyKeySequences = []struct {
+	seq []byte
+	key rune
+}{
+	{[]byte("\x1b[A"), KeyUp},
+	{[]byte("\x1b[B"), KeyDown},
+	{[]byte("\x1b[C"), KeyRight},
+	{[]byte("\x1b[D"), KeyLeft},
+	{[]byte("\x1bOA"), KeyUp},
+	{[]byte("\x1bOB"), KeyDown},
+	{[]byte("\x1bOC"), KeyRight},
+	{[]byte("\x1bOD"), KeyLeft},
+	{[]byte("\x1b[H"), KeyHome},
+	{[]byte("\x1b[F"), KeyEnd},
+	{[]byte("\x1bOH"), KeyHome},
+	{[]byte("\x1bOF"), KeyEnd},
+	{[]byte("\x1b[3~"), KeyDelete},
+}
+
+// AnyKeyOption configures an AnyKey prompt
+type AnyKeyOption func(*anyKeyPrompt)
+
+type anyKeyPrompt struct {
+	ctx       *Ctx
+	label     string
+	timeout   time.Duration
+	deadline  time.Time
+	timedOut  bool
+	cancelErr error
+}
+
+// AnyKey shows the label and waits for a single key to be pressed, without
+// waiting for enter, and returns it. Special keys are returned as the Key
+// constants and control keys as CtrlKey. Ctrl-C and Ctrl-D cancel the prompt.
+// Esc, and any escape sequence that is not one of the Key constants, is returned
+// as KeyEsc.
+func (ctx *Ctx) AnyKey(label string, opts ...AnyKeyOption) (rune, error) {
+	ak := &anyKeyPrompt{ctx: ctx, label: label}
+	for _, opt := range opts {
+		opt(ak)
+	}
+	return ak.run()
+}
+
+func AnyKey(label string, opts ...AnyKeyOption) (rune, error) {
+	return New().AnyKey(label, opts...)
+}
+
+// WithTimeout stops waiting for a key once the timeout has passed, counting
+// down the seconds left beside the label, and returns ErrTimedOut.
+func WithTimeout(timeout time.Duration) AnyKeyOption {
+	return func(ak *anyKeyPrompt) {
+		ak.timeout = timeout
+	}
+}
+
+func (ak *anyKeyPrompt) run() (rune, error) {
+	if ak.ctx.nonInteractive {
+		ak.ctx.Println(ak.label)
+		return 0, nil
+	}
+
+	restore, err := ak.ctx.rawMode()
+	if err != nil {
+		return 0, err
+	}
+	defer restore()
+
+	stdin := ak.ctx.keys().reader()
+	stdin.namedKeys = true
+	defer stdin.Close()
+	stop := context.AfterFunc(ak.ctx.Context(), func() { stdin.Close() })
+	defer stop()
+
+	type read struct {
+		chunk []byte
+		err   error
+	}
+	reads := make(chan read, 1)
+	go func() {
+		buf := make([]byte, 64)
+		n, err := stdin.Read(buf)
+		reads <- read{buf[:n], err}
+	}()
+
+	var timeout, tick <-chan time.Time
+	if ak.timeout > 0 {
+		ak.deadline = time.Now().Add(ak.timeout)
+		timer, ticker := time.NewTimer(ak.timeout), time.NewTicker(time.Second)
+		defer timer.Stop()
+		defer ticker.Stop()
+		timeout, tick = timer.C, ticker.C
+	}
+
+	sb := ak.ctx.newScreenBuf(ak.ctx.Writer())
+	ak.render(sb)
+	for {
+		select {
+		case <-tick:
+			ak.render(sb)
+		case <-timeout:
+			ak.timedOut = true
+			ak.render(sb)
+			return 0, ErrTimedOut
+		case r := <-reads:
+			if ak.ctx.Context().Err() != nil {
+				sb.Clear()
+				return 0, ak.ctx.Context().Err()
+			}
+			key, size := decodeKey(r.chunk)
+			if size < len(r.chunk) {
+				stdin.unread(r.chunk[size:])
+			}
+			if r.err == io.EOF || key == readline.CharDelete {
+				ak.cancelErr = ErrAborted
+			} else if key == readline.CharInterrupt {
+				ak.cancelErr = ErrInterrupted
+			} else if r.err != nil {
+				return 0, r.err
+			}
+			ak.deadline = time.Time{}
+			ak.render(sb)
+			return key, ak.cancelErr
+		}
+	}
+}
+
+// decodeKey reads the first key from the input, returning it and the amount of
+// the input that it used. Escape sequences that are not named are returned as
+// KeyEsc, like a lone Esc, using all of the sequence so that the rest of it is
+// not read as other keys.
+func decodeKey(input []byte) (rune, int) {
+	if len(input) == 0 {
+		return 0, 0
+	}
+	for _, sequence := range anyKeySequences {
+		if bytes.HasPrefix(input, sequence.seq) {
+			return sequence.key, len(sequence.seq)
+		}
+	}
+	if input[0] == readline.CharEsc {
+		return KeyEsc, keyLength(input)
+	}
+	return utf8.DecodeRune(input)
+}
+
+func (ak *anyKeyPrompt) render(sb *term.ScreenBuf) {
+	data := struct {
+		Prefix, Label, Countdown string
+		TimedOut, Cancelled      bool
+	}{
+		Prefix:    ak.ctx.Prefix(),
+		Label:     ak.label,
+		TimedOut:  ak.timedOut,
+		Cancelled: ak.cancelErr != nil,
+	}
+	if !ak.deadline.IsZero() {
+		seconds := int((time.Until(ak.deadline) + time.Second - 1) / time.Second)
+		data.Countdown = fmt.Sprintf("(%vs)", max(seconds, 0))
+	}
+	sb.WriteTmpl(anyKeyTemplate, data)
+}
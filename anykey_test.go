@@ -0,0 +1,95 @@
+package gluey_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestAnyKey(t *testing.T) {
+	for _, c := range []struct {
+		key  string
+		want rune
+	}{
+		{"a", 'a'},
+		{"é", 'é'},
+		{gluetest.KeyUp, gluey.KeyUp},
+		{"\x1bOD", gluey.KeyLeft},
+		{"\x1b[3~", gluey.KeyDelete},
+		{gluetest.KeyEsc, gluey.KeyEsc},
+		{"\x1b[1;5A", gluey.KeyEsc},
+		{"\x1b[15~", gluey.KeyF5},
+		{"\x1b[99~", gluey.KeyEsc},
+	} {
+		term := gluetest.NewTerminal()
+		term.Type(c.key)
+		if got, err := term.Ctx().AnyKey("Press a key"); err != nil || got != c.want {
+			t.Errorf("AnyKey(%q) = %q, %v, want %q", c.key, got, err, c.want)
+		}
+	}
+}
+
+func TestAnyKeyLeavesTypeahead(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("ab")
+	ctx := term.Ctx()
+	for _, want := range []rune{'a', 'b'} {
+		if got, err := ctx.AnyKey("Press a key"); err != nil || got != want {
+			t.Errorf("AnyKey() = %q, %v, want %q", got, err, want)
+		}
+	}
+}
+
+func TestAnyKeyLeavesKeysAfterAnUnknownSequence(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("\x1b[99~ab")
+	ctx := term.Ctx()
+	for _, want := range []rune{gluey.KeyEsc, 'a', 'b'} {
+		if got, err := ctx.AnyKey("Press a key"); err != nil || got != want {
+			t.Errorf("AnyKey() = %q, %v, want %q", got, err, want)
+		}
+	}
+}
+
+func TestAnyKeyCancelled(t *testing.T) {
+	for _, c := range []struct {
+		key  string
+		want error
+	}{
+		{gluetest.KeyCtrlC, gluey.ErrInterrupted},
+		{gluetest.KeyCtrlD, gluey.ErrAborted},
+	} {
+		term := gluetest.NewTerminal()
+		term.Type(c.key)
+		if _, err := term.Ctx().AnyKey("Press a key"); !errors.Is(err, c.want) {
+			t.Errorf("AnyKey(%q) error = %v, want %v", c.key, err, c.want)
+		}
+		if got, want := term.Screen(), "Press a key (cancelled)"; !strings.Contains(got, want) {
+			t.Errorf("screen = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestAnyKeyTimeout(t *testing.T) {
+	term := gluetest.NewTerminal()
+	if _, err := term.Ctx().AnyKey("Press a key", gluey.WithTimeout(50*time.Millisecond)); !errors.Is(err, gluey.ErrTimedOut) {
+		t.Fatalf("AnyKey() error = %v, want %v", err, gluey.ErrTimedOut)
+	}
+	if got, want := term.Screen(), "Press a key (timed out)"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestAnyKeyNonInteractive(t *testing.T) {
+	term := gluetest.NewTerminal()
+	if got, err := term.Ctx(gluey.NonInteractive()).AnyKey("Press a key"); err != nil || got != 0 {
+		t.Errorf("AnyKey() = %q, %v", got, err)
+	}
+	if got, want := term.Screen(), "Press a key"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
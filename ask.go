@@ -1,20 +1,21 @@
 package gluey
 
 import (
-	"bufio"
-	"os"
-	"path/filepath"
-	"strings"
-
 	"github.com/chzyer/readline"
 	"github.com/tanema/gluey/term"
 )
 
+const askTemplate = `{{.Prefix}}{{iconQ}} {{.Label}}
+{{- if .Default }} {{ .Default | faint }}{{ end }}
+{{- if .Err }}
+{{.Prefix}}{{iconBad}} {{ .Err | red }}
+{{- end }}`
+
 // Ask will prompt the user for a string input and will not return until a value
 // is passed. If the value is an empty string, the user will be re-prompted.
 func (ctx *Ctx) Ask(label string) (input string, err error) {
 	ctx.Println(Fmt(`{{iconQ}} {{.}}`, label))
-	for input = ""; input == "" && err == nil; input, err = ctx.ask("") {
+	for input = ""; input == "" && err == nil; input, err = ctx.ask(label, "") {
 	}
 	return input, err
 }
@@ -27,91 +28,112 @@ func Ask(label string) (input string, err error) {
 // string then the defalt value will be returned
 func (ctx *Ctx) AskDefault(label, what string) (string, error) {
 	ctx.Println(Fmt(`{{iconQ}} {{.Lab}} {{.Def | faint}}`, struct{ Lab, Def string }{label, "[default = " + what + "]"}))
-	return ctx.ask(what)
+	return ctx.ask(label, what)
 }
 
 func AskDefault(label, what string) (string, error) {
 	return New().AskDefault(label, what)
 }
 
-// AskFile will prompt the user for a filepath with autocomplete
-func (ctx *Ctx) AskFile(label string) (string, error) {
-	ctx.Println(Fmt(`{{iconQ}} {{.}}`, label))
-	c := &readline.Config{
-		AutoComplete: &fnCompleter{},
-		Stdin:        os.Stdin,
-	}
-	if err := c.Init(); err != nil {
-		return "", err
-	}
-	rl, err := readline.NewEx(c)
-	if err != nil {
-		return "", err
-	}
-	defer rl.Close()
-	return rl.Readline()
-}
-
-func AskFile(label string) (string, error) {
-	return New().AskFile(label)
+// AskValidated will prompt the user for a string input and will re-prompt until
+// the input passes validation. The validation error is shown beneath the prompt.
+func (ctx *Ctx) AskValidated(label string, validate Validator) (string, error) {
+	return ctx.askValid(label, "", validate)
 }
 
-func (ctx *Ctx) AnyKey(label string) error {
-	ctx.Println(label)
-	_, _, err := bufio.NewReader(os.Stdin).ReadRune()
-	return err
+func AskValidated(label string, validate Validator) (string, error) {
+	return New().AskValidated(label, validate)
 }
 
-func AnyKey(label string) error {
-	return New().AnyKey(label)
+func (ctx *Ctx) ask(label, what string) (string, error) {
+	result, _, err := ctx.readAnswer(label, what, nil)
+	return result, err
 }
 
-func (ctx *Ctx) ask(what string) (string, error) {
+// readAnswer reads a single answer, which is validated when validate is not nil.
+// The input is only saved to the history once it is valid. Validation errors
+// are returned apart from errors reading the answer.
+func (ctx *Ctx) readAnswer(label, what string, validate Validator) (result string, verr, err error) {
 	prompt := Fmt(`{{.}}{{blue ">"}} {{yellow ">>"}}`, ctx.Prefix())
-	rdl, err := readline.New(prompt)
-	if err != nil {
-		return "", err
+	if ctx.nonInteractive {
+		result, err := ctx.answer(label, what)
+		if err != nil {
+			return "", nil, err
+		}
+		ctx.Println(Fmt(`{{blue ">"}} {{.|yellow}}`, result))
+		if validate != nil {
+			verr = validate(result)
+		}
+		return result, verr, nil
 	}
 
-	result, err := rdl.Readline()
+	rdl, err := ctx.newLineReader(ctx.historyConfig(label, &readline.Config{Prompt: prompt}))
 	if err != nil {
-		return "", err
+		return "", nil, err
+	}
+	defer rdl.Close()
+
+	input, err := rdl.Readline()
+	if ctx.Context().Err() != nil {
+		return "", nil, ctx.cancelled(rdl, err, 1)
+	} else if ierr := interruption(err); ierr != nil {
+		ctx.aborted(rdl, label, 1+ctx.inputLines())
+		return "", nil, ierr
+	} else if err != nil {
+		return "", nil, err
 	}
 
+	result = input
 	if what != "" && result == "" {
 		term.ClearLines(ctx.Writer(), 1)
 		ctx.Println(prompt + Fmt(`{{.|yellow}} `, what))
 		result = what
 	}
-	return result, nil
-}
-
-// AskPassword prompts the user for a password input. Characters are not echoed
-func (ctx *Ctx) AskPassword(label string) (string, error) {
-	rdl, err := readline.New("")
-	if err != nil {
-		return "", err
+	if validate != nil {
+		verr = validate(result)
 	}
-	result, err := rdl.ReadPassword(Fmt(`{{iconQ}} {{.}}`, label))
-	return string(result), err
-}
-
-func AskPassword(label string) (string, error) {
-	return New().AskPassword(label)
+	if verr == nil {
+		rdl.saveHistory(input)
+	}
+	return result, verr, nil
 }
 
-type fnCompleter struct{}
-
-func (fc *fnCompleter) Do(line []rune, pos int) ([][]rune, int) {
-	sug, _ := filepath.Glob(string(line) + "*")
-	items := [][]rune{}
-	for _, item := range sug {
-		suggestion := strings.TrimPrefix(item, string(line))
-		info, err := os.Stat(item)
-		if err == nil && info.Mode().IsDir() {
-			suggestion += string(os.PathSeparator)
+// askValid asks until the input passes validation, rendering the label and the
+// validation error in place above the input. If what is not empty it is used
+// when the input is empty and shown as the default.
+func (ctx *Ctx) askValid(label, what string, validate Validator) (string, error) {
+	data := struct {
+		Prefix, Label, Default string
+		Err                    error
+	}{Prefix: ctx.Prefix(), Label: label}
+	if what != "" {
+		data.Default = "[default = " + what + "]"
+	}
+	sb := ctx.newScreenBuf(ctx.Writer())
+	sb.WriteTmpl(askTemplate, data)
+	for {
+		result, verr, err := ctx.readAnswer(label, what, validate)
+		if err != nil {
+			if ctx.Context().Err() != nil && data.Err != nil {
+				term.ClearLines(ctx.Writer(), 1)
+			} else if (err == ErrInterrupted || err == ErrAborted) && data.Err != nil {
+				// the error was replaced by the cancelled mark, so replace the label too
+				term.ClearLines(ctx.Writer(), 2)
+				ctx.markCancelled(label)
+			}
+			return "", err
+		}
+		if verr == nil && data.Err == nil {
+			return result, nil
+		} else if ctx.nonInteractive && verr != nil {
+			return "", &AnswerError{Label: label, Answer: result, Err: verr}
+		}
+		term.ClearLines(ctx.Writer(), 1)
+		data.Err = verr
+		sb.WriteTmpl(askTemplate, data)
+		if verr == nil {
+			ctx.Println(Fmt(`{{blue ">"}} {{.|yellow}}`, result))
+			return result, nil
 		}
-		items = append(items, []rune(suggestion))
 	}
-	return items, len(line)
 }
@@ -0,0 +1,58 @@
+package gluey_test
+
+import (
+	"testing"
+
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestAsk(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("h", "i", gluetest.KeyEnter)
+	input, err := term.Ctx().Ask("Name?")
+	if err != nil || input != "hi" {
+		t.Fatalf("Ask = %q, %v", input, err)
+	}
+	if got, want := term.Screen(), "? Name?\n> hi"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestAskRepromptsOnEmpty(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyEnter, "x", gluetest.KeyEnter)
+	if input, err := term.Ctx().Ask("Name?"); err != nil || input != "x" {
+		t.Errorf("Ask = %q, %v", input, err)
+	}
+}
+
+func TestAskDefault(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyEnter)
+	input, err := term.Ctx().AskDefault("Name?", "anon")
+	if err != nil || input != "anon" {
+		t.Errorf("AskDefault = %q, %v", input, err)
+	}
+	if got, want := term.Lines()[0], "? Name? [default = anon]"; got != want {
+		t.Errorf("label = %q, want %q", got, want)
+	}
+}
+
+func TestAskSkipsKeysThatReadlineDrops(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("\x00", "\x1b[99~", "\x1bOP", gluetest.KeyEsc, "h", "i", gluetest.KeyEnter)
+	if input, err := term.Ctx().Ask("Name?"); err != nil || input != "hi" {
+		t.Errorf("Ask = %q, %v", input, err)
+	}
+}
+
+func TestAskLeavesPastedInputForTheNextPrompt(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("one\rtwo\r")
+	ctx := term.Ctx()
+	for _, want := range []string{"one", "two"} {
+		if input, err := ctx.Ask("Name?"); err != nil || input != want {
+			t.Errorf("Ask = %q, %v, want %q", input, err, want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package gluey
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Loader loads the options of an async select. It should call add as options
+// are loaded and return once they are all loaded or c is done. The query is the
+// filter term when the select uses WithQueryLoad, otherwise it is empty.
+type Loader func(c context.Context, query string, add func(labels ...string)) error
+
+// SelectAsync will prompt the user with a list of options that are loaded by
+// load while the list is shown, and will allow them to select a single option.
+// Options that take an item index do not apply to the loaded options.
+func (ctx *Ctx) SelectAsync(label string, load Loader, opts ...SelectOption) (string, error) {
+	sel := newSelect(ctx, label, nil, opts...)
+	sel.loader = load
+	_, items, err := sel.runAsync()
+	if len(items) == 0 {
+		return "", err
+	}
+	return items[0], err
+}
+
+func SelectAsync(label string, load Loader, opts ...SelectOption) (string, error) {
+	return New().SelectAsync(label, load, opts...)
+}
+
+// SelectMultipleAsync will prompt the user with a list of options that are
+// loaded by load while the list is shown, and will allow them to select
+// multiple options.
+func (ctx *Ctx) SelectMultipleAsync(label string, load Loader, opts ...SelectOption) ([]string, error) {
+	sel := newMultipleSelect(ctx, label, nil, opts...)
+	sel.loader = load
+	_, items, err := sel.runAsync()
+	return items, err
+}
+
+func SelectMultipleAsync(label string, load Loader, opts ...SelectOption) ([]string, error) {
+	return New().SelectMultipleAsync(label, load, opts...)
+}
+
+// WithQueryLoad will reload the options of an async select with the filter term
+// as the query each time that it changes, instead of filtering the options that
+// have already been loaded.
+func WithQueryLoad() SelectOption {
+	return func(s *Selector) {
+		s.queryLoad = true
+	}
+}
+
+// runAsync runs the select, loading all of the options first when there is no
+// one to interact with.
+func (s *Selector) runAsync() ([]int, []string, error) {
+	if s.ctx.nonInteractive {
+		if err := s.loader(s.ctx.Context(), "", s.add); err != nil {
+			return []int{}, []string{}, err
+		}
+	}
+	return s.run()
+}
+
+// load starts loading the options for the query, stopping any previous load.
+// Options loaded for a query replace the previous ones, except for those that
+// have been chosen.
+func (s *Selector) load(query string) {
+	if s.stopLoad != nil {
+		s.stopLoad()
+	}
+	c, stop := context.WithCancel(s.ctx.Context())
+	s.stopLoad = stop
+	s.loading, s.loadErr = true, nil
+	if s.queryLoad {
+		s.items = s.selectedItems()
+		for i, item := range s.items {
+			item.Index = i + 1
+		}
+		s.rescope()
+	}
+
+	go func() {
+		err := s.loader(c, query, func(labels ...string) {
+			s.mut.Lock()
+			defer s.mut.Unlock()
+			if c.Err() == nil {
+				s.add(labels...)
+				s.render(s.screen)
+			}
+		})
+		s.mut.Lock()
+		defer s.mut.Unlock()
+		if c.Err() == nil {
+			s.loading, s.loadErr = false, err
+			s.render(s.screen)
+		}
+	}()
+
+	go func() {
+		for {
+			time.Sleep(80 * time.Millisecond)
+			s.mut.Lock()
+			if c.Err() != nil || !s.loading {
+				s.mut.Unlock()
+				return
+			}
+			s.spin++
+			s.render(s.screen)
+			s.mut.Unlock()
+		}
+	}()
+}
+
+// stopLoading stops any load, and any preview, so that nothing is rendered in
+// the background once the select is done.
+func (s *Selector) stopLoading() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.stopLoad != nil {
+		s.stopLoad()
+	}
+	s.loading = false
+	s.screen = nil
+}
+
+// add appends loaded options, keeping the cursor on the same item
+func (s *Selector) add(labels ...string) {
+	for _, label := range labels {
+		s.items = append(s.items, &selectItem{Label: label, Index: len(s.items) + 1})
+	}
+	s.rescope()
+}
+
+// rescope updates the items in scope after the items have changed, keeping the
+// cursor on the same item.
+func (s *Selector) rescope() {
+	var current *selectItem
+	if s.cursor < len(s.scope) {
+		current = s.scope[s.cursor]
+	}
+	cursor, start := s.cursor, s.start
+	s.number()
+	if s.mode == filtering && !s.queryLoad {
+		s.search(s.searchTerm)
+	} else {
+		s.scope = s.sorted(slices.Clone(s.items))
+	}
+	s.cursor, s.start = cursor, start
+	if i := slices.Index(s.scope, current); i >= 0 {
+		s.SetCursor(i)
+	} else {
+		s.SetCursor(cursor)
+	}
+}
@@ -0,0 +1,104 @@
+package gluey_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestSelectAsync(t *testing.T) {
+	term := gluetest.NewTerminal()
+	next := make(chan struct{})
+	load := func(c context.Context, query string, add func(labels ...string)) error {
+		add("a", "b")
+		select {
+		case <-next:
+		case <-c.Done():
+			return c.Err()
+		}
+		add("c")
+		return nil
+	}
+	done := make(chan string)
+	go func() {
+		item, _ := term.Ctx().SelectAsync("Pick", load)
+		done <- item
+	}()
+	waitFor(t, term, "Loading")
+	close(next)
+	waitFor(t, term, "3  c")
+	term.Type(gluetest.KeyUp, gluetest.KeyEnter)
+	if item := <-done; item != "c" {
+		t.Errorf("SelectAsync = %q", item)
+	}
+}
+
+func TestSelectAsyncWhileLoading(t *testing.T) {
+	term := gluetest.NewTerminal()
+	load := func(c context.Context, query string, add func(labels ...string)) error {
+		add("item")
+		<-c.Done()
+		return c.Err()
+	}
+	done := make(chan string)
+	go func() {
+		item, _ := term.Ctx().SelectAsync("Pick", load)
+		done <- item
+	}()
+	waitFor(t, term, "1  item")
+	term.Type("1")
+	if item := <-done; item != "item" {
+		t.Errorf("SelectAsync = %q", item)
+	}
+}
+
+func TestSelectAsyncQueryLoad(t *testing.T) {
+	term := gluetest.NewTerminal()
+	load := func(c context.Context, query string, add func(labels ...string)) error {
+		if query != "" {
+			add(query+" 1", query+" 2")
+		}
+		return nil
+	}
+	done := make(chan []string)
+	go func() {
+		items, _ := term.Ctx().SelectMultipleAsync("Pick", load, gluey.WithQueryLoad())
+		done <- items
+	}()
+	term.Type("f", "x")
+	waitFor(t, term, "x 2")
+	term.Type(gluetest.KeyDown, gluetest.KeyEnter, gluetest.KeyEsc)
+	waitFor(t, term, "Done")
+	term.Type("0")
+	if items := <-done; !slices.Equal(items, []string{"x 2"}) {
+		t.Errorf("SelectMultipleAsync = %q", items)
+	}
+}
+
+func TestSelectAsyncError(t *testing.T) {
+	term := gluetest.NewTerminal()
+	load := func(c context.Context, query string, add func(labels ...string)) error {
+		add("a")
+		return errors.New("connection lost")
+	}
+	done := make(chan string)
+	go func() {
+		item, _ := term.Ctx().SelectAsync("Pick", load)
+		done <- item
+	}()
+	waitFor(t, term, "connection lost")
+	term.Type(gluetest.KeyEnter)
+	if item := <-done; item != "a" {
+		t.Errorf("SelectAsync = %q", item)
+	}
+
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Pick": "a"}))
+	if _, err := ctx.SelectAsync("Pick", load); err == nil || !strings.Contains(err.Error(), "connection lost") {
+		t.Errorf("non interactive SelectAsync = %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package gluey
+
+import (
+	"cmp"
+	"strings"
+	"unicode"
+
+	"github.com/chzyer/readline"
+	"github.com/tanema/gluey/term"
+)
+
+const completeTemplate = `{{.Prefix}}
+{{- if .Cancelled -}}
+	{{ iconBad }} {{ .Label }} {{ "(cancelled)" | faint }}
+{{- else -}}
+{{ iconQ }} {{ .Label }}{{ if not .Done }} {{ .HelpText | yellow }}{{ end }}
+{{ .Prefix }}{{ blue ">" }} {{ if .Done -}}
+	{{ .Before | yellow }}
+{{- else -}}
+	{{ .Before }}{{ .At | underline }}{{ .After }}
+{{- end }}
+{{- range $index, $candidate := .Candidates }}
+{{ $.Prefix }}
+	{{- if eq $.Highlight $index -}}
+		{{ iconSel | blue }} {{ $candidate | blue }}
+	{{- else -}}
+		{{ "  " }}{{ $candidate }}
+	{{- end }}
+{{- end }}
+{{- with .Err }}
+{{ $.Prefix }}{{ iconBad }} {{ . | red }}
+{{- end }}
+{{- end }}`
+
+// completeHeight is the most candidates that the completion dropdown will show
+const completeHeight = 6
+
+// Completer suggests completions for the input of AskComplete
+type Completer interface {
+	// Complete returns the candidates for the input, each being the whole input
+	// as it would be once completed.
+	Complete(input string) []string
+}
+
+// CompleterFunc allows a function to be used as a Completer
+type CompleterFunc func(input string) []string
+
+// Complete returns the candidates from the function
+func (fn CompleterFunc) Complete(input string) []string {
+	return fn(input)
+}
+
+// ListCompleter completes the input with the candidates that start with it,
+// ignoring case.
+func ListCompleter(candidates ...string) Completer {
+	return CompleterFunc(func(input string) []string {
+		matches := []string{}
+		for _, candidate := range candidates {
+			if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(input)) {
+				matches = append(matches, candidate)
+			}
+		}
+		return matches
+	})
+}
+
+// completion is an input line with a dropdown of completions
+type completion struct {
+	ctx        *Ctx
+	label      string
+	completer  Completer
+	validate   Validator
+	allowEmpty bool
+	input      []rune
+	col        int
+	candidates []string
+	cursor     int
+	start      int
+	done       bool
+	cancelErr  error
+	err        error
+}
+
+// AskComplete will prompt the user for a string input, showing the candidates
+// from the completer in a dropdown beneath the input as they type. Tab fills in
+// the text that the candidates have in common or moves through them, as do ↑
+// and ↓, and Enter chooses the highlighted candidate or submits the input.
+func (ctx *Ctx) AskComplete(label string, completer Completer) (string, error) {
+	return ctx.askComplete(label, completer, nil, false)
+}
+
+func AskComplete(label string, completer Completer) (string, error) {
+	return New().AskComplete(label, completer)
+}
+
+// askComplete asks for input with completion, only accepting input that passes
+// validation when validate is not nil, and empty input when allowEmpty is set.
+func (ctx *Ctx) askComplete(label string, completer Completer, validate Validator, allowEmpty bool) (string, error) {
+	if ctx.nonInteractive {
+		ctx.Println(Fmt(`{{iconQ}} {{.}}`, label))
+		result, err := ctx.ask(label, "")
+		if err == nil && validate != nil {
+			if verr := validate(result); verr != nil {
+				return "", &AnswerError{Label: label, Answer: result, Err: verr}
+			}
+		}
+		return result, err
+	}
+	ac := &completion{ctx: ctx, label: label, completer: completer, validate: validate, allowEmpty: allowEmpty}
+	ac.refresh()
+	return ac.run()
+}
+
+func (ac *completion) run() (string, error) {
+	rl, err := ac.ctx.newLineReader(&readline.Config{
+		HistoryLimit:   -1,
+		UniqueEditLine: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	rl.stdin.namedKeys = true
+	if err := rl.readKeys(ac); err != nil {
+		return "", err
+	}
+	return string(ac.input), nil
+}
+
+func (ac *completion) finished() bool {
+	return ac.done || ac.cancelErr != nil
+}
+
+func (ac *completion) cancel(err error) error {
+	ac.cancelErr = cmp.Or(ac.cancelErr, err)
+	return ac.cancelErr
+}
+
+func (ac *completion) listen(key rune) {
+	ac.err = nil
+	switch key {
+	case readline.CharInterrupt:
+		ac.cancelErr = ErrInterrupted
+	case readline.CharDelete:
+		if len(ac.input) == 0 {
+			ac.cancelErr = ErrAborted
+		} else if ac.col < len(ac.input) {
+			ac.input = append(ac.input[:ac.col], ac.input[ac.col+1:]...)
+			ac.refresh()
+		}
+	case readline.CharEnter:
+		if ac.cursor >= 0 {
+			ac.accept(ac.candidates[ac.cursor])
+		} else if len(ac.input) > 0 || ac.allowEmpty {
+			if ac.validate != nil {
+				ac.err = ac.validate(string(ac.input))
+			}
+			ac.done = ac.err == nil
+		}
+	case readline.CharTab:
+		if common := commonPrefix(ac.candidates); ac.cursor < 0 && len([]rune(common)) > len(ac.input) {
+			ac.accept(common)
+		} else if len(ac.candidates) == 1 {
+			ac.accept(ac.candidates[0])
+		} else {
+			ac.highlight(1)
+		}
+	case readline.CharNext:
+		ac.highlight(1)
+	case readline.CharPrev:
+		ac.highlight(-1)
+	case KeyEsc:
+		ac.highlight(-ac.cursor - 1)
+	case readline.CharBackspace, readline.CharCtrlH:
+		if ac.col > 0 {
+			ac.input = append(ac.input[:ac.col-1], ac.input[ac.col:]...)
+			ac.col--
+			ac.refresh()
+		}
+	case readline.CharBackward:
+		ac.col = max(ac.col-1, 0)
+	case readline.CharForward:
+		ac.col = min(ac.col+1, len(ac.input))
+	case readline.CharLineStart:
+		ac.col = 0
+	case readline.CharLineEnd:
+		ac.col = len(ac.input)
+	default:
+		if unicode.IsPrint(key) {
+			ac.input = append(ac.input[:ac.col], append([]rune{key}, ac.input[ac.col:]...)...)
+			ac.col++
+			ac.refresh()
+		}
+	}
+}
+
+// refresh gets the candidates for the current input, without any highlighted
+func (ac *completion) refresh() {
+	ac.candidates = ac.completer.Complete(string(ac.input))
+	ac.cursor, ac.start = -1, 0
+}
+
+// accept replaces the input with the candidate
+func (ac *completion) accept(candidate string) {
+	ac.input = []rune(candidate)
+	ac.col = len(ac.input)
+	ac.refresh()
+}
+
+// highlight moves the highlight through the candidates, wrapping around through
+// the input where nothing is highlighted.
+func (ac *completion) highlight(by int) {
+	count := len(ac.candidates) + 1
+	ac.cursor = ((ac.cursor+1+by)%count+count)%count - 1
+	size := ac.size()
+	if ac.cursor < 0 {
+		ac.start = 0
+	} else if ac.cursor < ac.start {
+		ac.start = ac.cursor
+	} else if ac.cursor >= ac.start+size {
+		ac.start = ac.cursor - size + 1
+	}
+}
+
+func (ac *completion) size() int {
+	return max(min(completeHeight, ac.ctx.height()/3), 1)
+}
+
+func (ac *completion) render(sb *term.ScreenBuf) {
+	data := struct {
+		Prefix, Label, HelpText string
+		Before, At, After       string
+		Candidates              []string
+		Highlight               int
+		Done, Cancelled         bool
+		Err                     error
+	}{
+		Prefix:    ac.ctx.Prefix(),
+		Label:     ac.label,
+		HelpText:  "(Tab to complete, ↑ ↓ to choose)",
+		Before:    string(ac.input),
+		Highlight: ac.cursor - ac.start,
+		Done:      ac.done,
+		Cancelled: ac.cancelErr != nil,
+		Err:       ac.err,
+	}
+	if !ac.done {
+		data.Before, data.At = string(ac.input[:ac.col]), " "
+		if ac.col < len(ac.input) {
+			data.At, data.After = string(ac.input[ac.col]), string(ac.input[ac.col+1:])
+		}
+		data.Candidates = ac.candidates[ac.start:min(ac.start+ac.size(), len(ac.candidates))]
+	}
+	sb.WriteTmpl(completeTemplate, data)
+}
+
+// commonPrefix finds the longest prefix that all of the candidates share
+func commonPrefix(candidates []string) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+	prefix := []rune(candidates[0])
+	for _, candidate := range candidates[1:] {
+		runes := []rune(candidate)
+		i := 0
+		for i < len(prefix) && i < len(runes) && prefix[i] == runes[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	return string(prefix)
+}
@@ -0,0 +1,59 @@
+package gluey_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+var colors = gluey.ListCompleter("red", "green", "grey", "blue")
+
+func TestAskComplete(t *testing.T) {
+	cases := []struct {
+		name string
+		keys []string
+		want string
+	}{
+		{"typed", []string{"pink", gluetest.KeyEnter}, "pink"},
+		{"tab completes the common prefix", []string{"g", gluetest.KeyTab, "e", gluetest.KeyTab, gluetest.KeyEnter}, "green"},
+		{"tab moves through the candidates", []string{"g", "r", gluetest.KeyTab, gluetest.KeyTab, gluetest.KeyTab, gluetest.KeyEnter, gluetest.KeyEnter}, "grey"},
+		{"down chooses a candidate", []string{gluetest.KeyDown, gluetest.KeyEnter, gluetest.KeyEnter}, "red"},
+		{"up wraps", []string{gluetest.KeyUp, gluetest.KeyEnter, gluetest.KeyEnter}, "blue"},
+		{"esc leaves the input", []string{"b", gluetest.KeyDown, gluetest.KeyEsc, "x", gluetest.KeyEnter}, "bx"},
+		{"ignores empty input", []string{gluetest.KeyEnter, "r", gluetest.KeyEnter}, "r"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := gluetest.NewTerminal()
+			term.Type(c.keys...)
+			if got, err := term.Ctx().AskComplete("Color?", colors); err != nil || got != c.want {
+				t.Errorf("AskComplete = %q, %v, want %q", got, err, c.want)
+			}
+		})
+	}
+}
+
+func TestAskCompleteDropdown(t *testing.T) {
+	term := gluetest.NewTerminal()
+	done := make(chan string)
+	go func() {
+		color, _ := term.Ctx().AskComplete("Color?", colors)
+		done <- color
+	}()
+	term.Type("g")
+	waitFor(t, term, "> g")
+	if screen := term.Screen(); strings.Contains(screen, "red") || !strings.Contains(screen, "green") {
+		t.Errorf("dropdown shows candidates that do not match:\n%s", screen)
+	}
+	term.Type(gluetest.KeyCtrlC)
+	<-done
+}
+
+func TestAskCompleteNonInteractive(t *testing.T) {
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Color?": "teal"}))
+	if got, err := ctx.AskComplete("Color?", colors); err != nil || got != "teal" {
+		t.Errorf("AskComplete = %q, %v", got, err)
+	}
+}
@@ -1,41 +1,274 @@
 package gluey
 
 import (
+	"cmp"
 	"fmt"
 	"slices"
 	"strings"
+	"unicode"
+
+	"github.com/chzyer/readline"
+	"github.com/tanema/gluey/term"
 )
 
-var validConfirmAnswers = []string{"yes", "no", "y", "n"}
+const confirmTemplate = `{{.Prefix}}
+{{- if .Cancelled -}}
+	{{ iconBad }} {{ .Label }} {{ "(cancelled)" | faint }}
+{{- else if .Done -}}
+	{{ iconQ }} {{ .Label }} (You chose: {{ .Answer | italic }})
+{{- else -}}
+	{{ iconQ }} {{ .Label }} {{ .Hint | faint }} {{ .Input }}{{ " " | underline }}
+{{- with .Err }}
+{{ $.Prefix }}{{ iconBad }} {{ . | red }}
+{{- end }}
+{{- end }}`
+
+// ConfirmOption configures a confirm prompt
+type ConfirmOption func(*confirmPrompt)
+
+type confirmPrompt struct {
+	ctx        *Ctx
+	label      string
+	hasDefault bool
+	dflt       bool
+	keypress   bool
+	yes, no    []string
+	input      []rune
+	result     bool
+	err        error
+	done       bool
+	cancelErr  error
+}
+
+// Confirm will ask a yes or no question and wait for an answer that is one of
+// those.
+func (ctx *Ctx) Confirm(question string, opts ...ConfirmOption) (bool, error) {
+	return newConfirm(ctx, question, opts).run()
+}
+
+func Confirm(question string, opts ...ConfirmOption) (bool, error) {
+	return New().Confirm(question, opts...)
+}
+
+// ConfirmDefault will ask a yes or no question, answering dflt when the user
+// just presses enter. The default is shown capitalized, like [Y/n].
+func (ctx *Ctx) ConfirmDefault(question string, dflt bool, opts ...ConfirmOption) (bool, error) {
+	cp := newConfirm(ctx, question, opts)
+	cp.hasDefault, cp.dflt = true, dflt
+	return cp.run()
+}
+
+func ConfirmDefault(question string, dflt bool, opts ...ConfirmOption) (bool, error) {
+	return New().ConfirmDefault(question, dflt, opts...)
+}
+
+// WithKeypress answers a confirm as soon as a key is pressed that starts one of
+// the answers, without waiting for enter. Yes answers are checked first.
+func WithKeypress() ConfirmOption {
+	return func(cp *confirmPrompt) {
+		cp.keypress = true
+	}
+}
 
-// Confirm will as a yes or no question and wait for an answer that is one of those.
-func (ctx *Ctx) Confirm(question string) (bool, error) {
-	for {
-		if response, err := ctx.Ask(fmt.Sprintf("%s [y/n]: ", question)); err != nil {
-			return false, err
-		} else if res := strings.ToLower(strings.TrimSpace(response)); slices.Contains(validConfirmAnswers, res) {
-			return res == "y" || res == "yes", nil
+// WithVocabulary sets the answers accepted for yes and no, ignoring case, like
+// []string{"ja", "j"} and []string{"nein", "n"}. The first answers are shown as
+// the chosen answer and the shortest in the hint. Blank answers are ignored, and
+// the default answers are kept for yes or no if none are left.
+func WithVocabulary(yes, no []string) ConfirmOption {
+	isBlank := func(answer string) bool { return strings.TrimSpace(answer) == "" }
+	yes = slices.DeleteFunc(slices.Clone(yes), isBlank)
+	no = slices.DeleteFunc(slices.Clone(no), isBlank)
+	return func(cp *confirmPrompt) {
+		if len(yes) > 0 {
+			cp.yes = yes
+		}
+		if len(no) > 0 {
+			cp.no = no
 		}
 	}
 }
 
-func Confirm(question string) (bool, error) {
-	return New().Confirm(question)
+func newConfirm(ctx *Ctx, label string, opts []ConfirmOption) *confirmPrompt {
+	cp := &confirmPrompt{
+		ctx:   ctx,
+		label: label,
+		yes:   []string{"yes", "y"},
+		no:    []string{"no", "n"},
+	}
+	for _, opt := range opts {
+		opt(cp)
+	}
+	return cp
 }
 
 // ConfirmSelect will prompt the user with a yes/no option. The dflt setting will
-// decide if the first option is yes or no so that the user can just press enter
+// decide if the cursor starts on yes or no so that the user can just press enter
 func (ctx *Ctx) ConfirmSelect(label string, dflt bool) (bool, error) {
-	var err error
-	var result string
+	if ctx.nonInteractive {
+		cp := newConfirm(ctx, label, nil)
+		cp.hasDefault, cp.dflt = true, dflt
+		return cp.answer()
+	}
+	cursor := 2
 	if dflt {
-		_, result, err = ctx.Select(label, []string{"yes", "no"})
-	} else {
-		_, result, err = ctx.Select(label, []string{"no", "yes"})
+		cursor = 1
 	}
+	_, result, err := ctx.Select(label, []string{"yes", "no"}, WithDefault(cursor))
 	return result == "yes", err
 }
 
 func ConfirmSelect(label string, dflt bool) (bool, error) {
 	return New().ConfirmSelect(label, dflt)
 }
+
+func (cp *confirmPrompt) run() (bool, error) {
+	if cp.ctx.nonInteractive {
+		return cp.answer()
+	}
+
+	rl, err := cp.ctx.newLineReader(&readline.Config{
+		HistoryLimit:   -1,
+		UniqueEditLine: true,
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := rl.readKeys(cp); err != nil {
+		return false, err
+	}
+	return cp.result, nil
+}
+
+func (cp *confirmPrompt) finished() bool {
+	return cp.done || cp.cancelErr != nil
+}
+
+func (cp *confirmPrompt) cancel(err error) error {
+	cp.cancelErr = cmp.Or(cp.cancelErr, err)
+	return cp.cancelErr
+}
+
+// answer resolves the confirm from the context answers
+func (cp *confirmPrompt) answer() (bool, error) {
+	dflt := ""
+	if cp.hasDefault {
+		dflt = cp.answerFor(cp.dflt)
+	}
+	response, err := cp.ctx.answer(cp.label, dflt)
+	if err != nil {
+		return false, err
+	}
+	result, ok := cp.match(response)
+	if !ok {
+		return false, &AnswerError{Label: cp.label, Answer: response}
+	}
+	cp.ctx.Println(Fmt(`{{iconQ}} {{.Lab}} (You chose: {{.Res | italic}})`, struct{ Lab, Res string }{cp.label, cp.answerFor(result)}))
+	return result, nil
+}
+
+func (cp *confirmPrompt) listen(key rune) {
+	cp.err = nil
+	switch key {
+	case readline.CharInterrupt:
+		cp.cancelErr = ErrInterrupted
+	case readline.CharDelete:
+		if len(cp.input) == 0 {
+			cp.cancelErr = ErrAborted
+		}
+	case readline.CharEnter:
+		cp.submit()
+	case readline.CharBackspace, readline.CharCtrlH:
+		if len(cp.input) > 0 {
+			cp.input = cp.input[:len(cp.input)-1]
+		}
+	default:
+		if !unicode.IsPrint(key) {
+			return
+		}
+		cp.input = append(cp.input, key)
+		if cp.keypress {
+			cp.submit()
+		}
+	}
+}
+
+// submit answers the confirm with the input, or the default if the input is
+// empty.
+func (cp *confirmPrompt) submit() {
+	input := strings.TrimSpace(string(cp.input))
+	if input == "" && cp.hasDefault {
+		cp.result, cp.done = cp.dflt, true
+		return
+	} else if cp.keypress && len(cp.input) > 0 {
+		cp.result, cp.done = cp.matchKey(cp.input[len(cp.input)-1])
+	} else {
+		cp.result, cp.done = cp.match(input)
+	}
+	if !cp.done {
+		cp.input = cp.input[:0]
+		cp.err = fmt.Errorf("answer %v or %v", cp.yes[0], cp.no[0])
+	}
+}
+
+// match finds the answer that the input is, returning false if it is not one
+func (cp *confirmPrompt) match(input string) (result, ok bool) {
+	isAnswer := func(answer string) bool { return strings.EqualFold(answer, strings.TrimSpace(input)) }
+	if slices.ContainsFunc(cp.yes, isAnswer) {
+		return true, true
+	}
+	return false, slices.ContainsFunc(cp.no, isAnswer)
+}
+
+// matchKey finds the answer that starts with the key
+func (cp *confirmPrompt) matchKey(key rune) (result, ok bool) {
+	startsAnswer := func(answer string) bool {
+		for _, r := range answer {
+			return unicode.ToLower(r) == unicode.ToLower(key)
+		}
+		return false
+	}
+	if slices.ContainsFunc(cp.yes, startsAnswer) {
+		return true, true
+	}
+	return false, slices.ContainsFunc(cp.no, startsAnswer)
+}
+
+// answerFor is the answer shown when the result is chosen
+func (cp *confirmPrompt) answerFor(result bool) string {
+	if result {
+		return cp.yes[0]
+	}
+	return cp.no[0]
+}
+
+// hint shows the shortest answers, with the default capitalized
+func (cp *confirmPrompt) hint() string {
+	shortest := func(answers []string) string {
+		return slices.MinFunc(answers, func(a, b string) int { return len([]rune(a)) - len([]rune(b)) })
+	}
+	yes, no := strings.ToLower(shortest(cp.yes)), strings.ToLower(shortest(cp.no))
+	if cp.hasDefault && cp.dflt {
+		yes = strings.ToUpper(yes)
+	} else if cp.hasDefault {
+		no = strings.ToUpper(no)
+	}
+	return "[" + yes + "/" + no + "]"
+}
+
+func (cp *confirmPrompt) render(sb *term.ScreenBuf) {
+	sb.WriteTmpl(confirmTemplate, struct {
+		Prefix, Label, Hint string
+		Input, Answer       string
+		Err                 error
+		Done, Cancelled     bool
+	}{
+		Prefix:    cp.ctx.Prefix(),
+		Label:     cp.label,
+		Hint:      cp.hint(),
+		Input:     string(cp.input),
+		Answer:    cp.answerFor(cp.result),
+		Err:       cp.err,
+		Done:      cp.done,
+		Cancelled: cp.cancelErr != nil,
+	})
+}
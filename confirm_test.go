@@ -0,0 +1,98 @@
+package gluey_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestConfirm(t *testing.T) {
+	for _, c := range []struct {
+		keys []string
+		want bool
+	}{
+		{[]string{"y", gluetest.KeyEnter}, true},
+		{[]string{"n", "o", gluetest.KeyEnter}, false},
+		{[]string{"x", gluetest.KeyEnter, "Y", "e", "s", gluetest.KeyEnter}, true},
+	} {
+		term := gluetest.NewTerminal()
+		term.Type(c.keys...)
+		if got, err := term.Ctx().Confirm("Sure?"); err != nil || got != c.want {
+			t.Errorf("Confirm(%q) = %v, %v", c.keys, got, err)
+		}
+	}
+}
+
+func TestConfirmScreen(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("y", gluetest.KeyEnter)
+	term.Ctx().Confirm("Sure?")
+	if got, want := term.Screen(), "? Sure? (You chose: yes)"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestConfirmSelect(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyEnter)
+	if got, err := term.Ctx().ConfirmSelect("Sure?", false); err != nil || got {
+		t.Errorf("ConfirmSelect = %v, %v", got, err)
+	}
+}
+
+func TestConfirmDefault(t *testing.T) {
+	for _, dflt := range []bool{true, false} {
+		term := gluetest.NewTerminal()
+		term.Type(gluetest.KeyEnter)
+		if got, err := term.Ctx().ConfirmDefault("Sure?", dflt); err != nil || got != dflt {
+			t.Errorf("ConfirmDefault(%v) = %v, %v", dflt, got, err)
+		}
+	}
+}
+
+func TestConfirmKeypress(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("x", "N")
+	if got, err := term.Ctx().Confirm("Sure?", gluey.WithKeypress()); err != nil || got {
+		t.Errorf("Confirm = %v, %v", got, err)
+	}
+	if screen := term.Screen(); !strings.HasSuffix(screen, "(You chose: no)") {
+		t.Errorf("screen = %q", screen)
+	}
+}
+
+func TestConfirmVocabulary(t *testing.T) {
+	cases := []struct {
+		name     string
+		yes, no  []string
+		keys     []string
+		want     bool
+		hint     string
+		response string
+	}{
+		{"custom", []string{"ja", "j"}, []string{"nein", "n"}, []string{"j", gluetest.KeyEnter}, true, "[j/n]", "ja"},
+		{"empty yes", nil, []string{"nein", "n"}, []string{"y", gluetest.KeyEnter}, true, "[y/n]", "yes"},
+		{"empty no", []string{"ja"}, []string{}, []string{"no", gluetest.KeyEnter}, false, "[ja/n]", "no"},
+		{"blank", []string{""}, []string{" "}, []string{"n", gluetest.KeyEnter}, false, "[y/n]", "no"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := gluetest.NewTerminal()
+			done := make(chan bool)
+			go func() {
+				got, _ := term.Ctx().Confirm("Sure?", gluey.WithVocabulary(c.yes, c.no))
+				done <- got
+			}()
+			waitFor(t, term, c.hint)
+			term.Type(c.keys...)
+			if got := <-done; got != c.want {
+				t.Errorf("Confirm = %v", got)
+			}
+			if screen := term.Screen(); !strings.HasSuffix(screen, "(You chose: "+c.response+")") {
+				t.Errorf("screen = %q", screen)
+			}
+		})
+	}
+}
@@ -1,24 +1,467 @@
 package gluey
 
 import (
+	"context"
+	"errors"
+	"io"
 	"log"
+	"os"
+	"sync"
 
+	"github.com/chzyer/readline"
 	"github.com/k0kubun/go-ansi"
 	"github.com/tanema/gluey/term"
 )
 
-// Ctx allows use to keep a root object for all elements
+var (
+	// ErrInterrupted is returned by prompts when the user presses Ctrl-C
+	ErrInterrupted = errors.New("interrupted")
+	// ErrAborted is returned by prompts when the user presses Ctrl-D, or the
+	// input ends, to give up on the prompt
+	ErrAborted = errors.New("aborted")
+	// ErrNotConfirmed is returned by ConfirmPhrase when the phrase is not typed
+	// or the user presses Esc
+	ErrNotConfirmed = errors.New("not confirmed")
+	// ErrTimedOut is returned by AnyKey when no key is pressed before its
+	// timeout
+	ErrTimedOut = errors.New("timed out")
+)
+
+// Ctx allows use to keep a root object for all elements. A Ctx that is not built
+// with New reads from stdin and writes to stdout.
 type Ctx struct {
 	*log.Logger
-	Indent int
+	Indent         int
+	context        context.Context
+	in             io.Reader
+	input          *sharedInput
+	errOut         io.Writer
+	termWidth      int
+	termHeight     int
+	nonInteractive bool
+	answerPiped    bool
+	answers        answerChain
+	matcher        Matcher
+	historyDir     string
+	historyLimit   int
+	historyID      string
+	historyOff     bool
 }
 
+// Option configures a Ctx when it is built with New
+type Option func(*Ctx)
+
 // New builds a new UI context that every element will be based on
-func New() *Ctx {
-	return &Ctx{Logger: log.New(ansi.NewAnsiStdout(), "", 0)}
+func New(opts ...Option) *Ctx {
+	ctx := &Ctx{
+		Logger:  log.New(ansi.NewAnsiStdout(), "", 0),
+		in:      os.Stdin,
+		errOut:  ansi.NewAnsiStderr(),
+		matcher: FuzzyMatcher,
+	}
+	for _, opt := range opts {
+		opt(ctx)
+	}
+	if ctx.answerPiped && !isTerminal(ctx.in) {
+		ctx.nonInteractive = true
+	}
+	if ctx.in != os.Stdin {
+		ctx.input = newSharedInput(ctx.in)
+	}
+	return ctx
+}
+
+// stdinInput is shared by every context that reads from stdin, so that input
+// read by one is not lost to the next.
+var stdinInput = sync.OnceValue(func() *sharedInput { return newSharedInput(os.Stdin) })
+
+// stdoutLogger is used by contexts that have no Logger
+var stdoutLogger = sync.OnceValue(func() *log.Logger { return log.New(ansi.NewAnsiStdout(), "", 0) })
+
+// inputSource is the reader that the context reads input from
+func (ctx *Ctx) inputSource() io.Reader {
+	if ctx.in == nil {
+		return os.Stdin
+	}
+	return ctx.in
+}
+
+// keys is the shared input that prompts of the context read through
+func (ctx *Ctx) keys() *sharedInput {
+	if ctx.input == nil {
+		return stdinInput()
+	}
+	return ctx.input
+}
+
+// errWriter is the writer that the context writes errors to
+func (ctx *Ctx) errWriter() io.Writer {
+	if ctx.errOut == nil {
+		return os.Stderr
+	}
+	return ctx.errOut
+}
+
+func (ctx *Ctx) logger() *log.Logger {
+	if ctx.Logger == nil {
+		return stdoutLogger()
+	}
+	return ctx.Logger
+}
+
+// Writer returns the writer that elements render to
+func (ctx *Ctx) Writer() io.Writer {
+	return ctx.logger().Writer()
+}
+
+// Prefix returns the prefix of each line written by the context
+func (ctx *Ctx) Prefix() string {
+	return ctx.logger().Prefix()
+}
+
+// Println writes a line to the output of the context, after its prefix
+func (ctx *Ctx) Println(v ...any) {
+	ctx.logger().Println(v...)
+}
+
+// WithInput sets the reader that prompts will read input from. If the reader is
+// not a file, it is treated as a terminal so that it can be driven by a pty
+// or an ssh session.
+func WithInput(in io.Reader) Option {
+	return func(ctx *Ctx) {
+		ctx.in = in
+	}
+}
+
+// WithOutput sets the writer that all elements will render to.
+func WithOutput(out io.Writer) Option {
+	return func(ctx *Ctx) {
+		ctx.Logger = log.New(out, "", 0)
+	}
+}
+
+// WithErrOutput sets the writer that prompts will write errors to.
+func WithErrOutput(errOut io.Writer) Option {
+	return func(ctx *Ctx) {
+		ctx.errOut = errOut
+	}
+}
+
+// WithSize fixes the size of the terminal instead of detecting it from the
+// input, which is useful when the input is not a file.
+func WithSize(width, height int) Option {
+	return func(ctx *Ctx) {
+		ctx.termWidth = width
+		ctx.termHeight = height
+	}
+}
+
+// NonInteractive will make every prompt resolve its value from the answers
+// instead of reading from the terminal. Prompts with a default will fall back to
+// it when no answer is found.
+func NonInteractive(answers ...Answers) Option {
+	return func(ctx *Ctx) {
+		ctx.nonInteractive = true
+		ctx.answers = append(ctx.answers, answers...)
+	}
+}
+
+// WithAnswers will behave like NonInteractive but only when the input is a file
+// that is not a terminal, like when running in CI.
+func WithAnswers(answers ...Answers) Option {
+	return func(ctx *Ctx) {
+		ctx.answerPiped = true
+		ctx.answers = append(ctx.answers, answers...)
+	}
+}
+
+// WithContext returns a copy of the context that is bound to c. When c is done
+// any pending prompt is torn down, its render erased and c.Err() returned.
+func (ctx *Ctx) WithContext(c context.Context) *Ctx {
+	newCtx := *ctx
+	newCtx.context = c
+	return &newCtx
+}
+
+// Context returns the context.Context that the prompts are bound to
+func (ctx *Ctx) Context() context.Context {
+	if ctx.context == nil {
+		return context.Background()
+	}
+	return ctx.context
 }
 
 // Fmt will format a string template with color and icons
 func Fmt(template string, data any) string {
 	return term.Sprintf(template, data)
 }
+
+func (ctx *Ctx) width() int {
+	if ctx.termWidth > 0 {
+		return ctx.termWidth
+	}
+	w, _ := term.SizeOf(ctx.inputSource(), ctx.Writer())
+	return w
+}
+
+func (ctx *Ctx) height() int {
+	if ctx.termHeight > 0 {
+		return ctx.termHeight
+	}
+	_, h := term.SizeOf(ctx.inputSource(), ctx.Writer())
+	return h
+}
+
+func (ctx *Ctx) newScreenBuf(w io.Writer) *term.ScreenBuf {
+	sb := term.NewScreenBuf(w)
+	sb.SetWidthFunc(ctx.width)
+	return sb
+}
+
+// readlineConfig sets up a readline config to use the output streams and
+// terminal of the context.
+func (ctx *Ctx) readlineConfig(c *readline.Config) *readline.Config {
+	c.Stdout = ctx.Writer()
+	c.Stderr = ctx.errWriter()
+	c.FuncGetWidth = ctx.width
+	if f, ok := ctx.inputSource().(*os.File); ok {
+		var state *readline.State
+		c.FuncIsTerminal = func() bool { return isTerminal(f) }
+		c.FuncMakeRaw = func() (err error) {
+			state, err = readline.MakeRaw(int(f.Fd()))
+			return err
+		}
+		c.FuncExitRaw = func() error {
+			if state == nil {
+				return nil
+			}
+			return readline.Restore(int(f.Fd()), state)
+		}
+	} else {
+		c.FuncIsTerminal = func() bool { return true }
+		c.FuncMakeRaw = func() error { return nil }
+		c.FuncExitRaw = func() error { return nil }
+	}
+	return c
+}
+
+// rawMode puts the terminal of the context into raw mode, so that keys are read
+// as they are pressed, and returns a function that restores it. Nothing is done
+// if the input is not a terminal.
+func (ctx *Ctx) rawMode() (restore func(), err error) {
+	f, ok := ctx.inputSource().(*os.File)
+	if !ok || !isTerminal(f) {
+		return func() {}, nil
+	}
+	state, err := readline.MakeRaw(int(f.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	return func() { readline.Restore(int(f.Fd()), state) }, nil
+}
+
+// lineReader is a readline instance that will stop reading when the context
+// of the Ctx that created it is done. Input is only read once the previous key
+// has been handled, so that a key that finishes the prompt does not cause the
+// next key to be read away from the next prompt.
+type lineReader struct {
+	*readline.Instance
+	ctx      *Ctx
+	stdin    *inputReader
+	stop     func() bool
+	mut      sync.Mutex
+	listener func(line []rune, pos int, key rune) ([]rune, int, bool)
+	filter   func(key rune) bool
+	// hidden keeps the keys of a keyPrompt from readline, which would otherwise
+	// echo them and keep them in its buffer.
+	hidden bool
+}
+
+func (ctx *Ctx) newLineReader(c *readline.Config) (*lineReader, error) {
+	lr := &lineReader{ctx: ctx, stdin: ctx.keys().pacedReader()}
+	ctx.readlineConfig(c)
+	c.Stdin = lr.stdin
+	c.SetListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		if key != 0 {
+			// readline calls the listener without a key as it starts to read
+			defer lr.stdin.handled()
+		}
+		lr.mut.Lock()
+		defer lr.mut.Unlock()
+		if lr.listener == nil {
+			return nil, 0, false
+		}
+		return lr.listener(line, pos, key)
+	})
+	c.FuncFilterInputRune = func(key rune) (rune, bool) {
+		lr.mut.Lock()
+		defer lr.mut.Unlock()
+		if lr.filter == nil || lr.filter(key) {
+			return key, true
+		}
+		// the listener is not called for keys that readline does not handle
+		if key != 0 {
+			lr.stdin.handled()
+		}
+		return key, false
+	}
+	lr.stop = context.AfterFunc(ctx.Context(), func() { lr.stdin.Close() })
+	rl, err := readline.NewEx(c)
+	if err != nil {
+		lr.stop()
+		return nil, err
+	}
+	lr.Instance = rl
+	return lr, nil
+}
+
+// SetListener sets the function that is called after each key is handled by
+// readline. It must be set before reading.
+func (lr *lineReader) SetListener(fn func(line []rune, pos int, key rune) ([]rune, int, bool)) {
+	lr.listener = fn
+}
+
+// locked calls fn while no key is being handled, so that it can read the state
+// that the listener changes. readline returns a line before the listener has
+// handled its last key.
+func (lr *lineReader) locked(fn func() bool) bool {
+	lr.mut.Lock()
+	defer lr.mut.Unlock()
+	return fn()
+}
+
+// keyPrompt is a prompt that handles each key itself, rather than reading lines
+type keyPrompt interface {
+	listen(key rune)
+	render(sb *term.ScreenBuf)
+	// finished is true once the prompt is done or cancelled
+	finished() bool
+	// cancel is called with the interruption that stopped reading, or nil, and
+	// returns the error that the prompt was cancelled with, if it was.
+	cancel(err error) error
+}
+
+// readKeys passes each key to the prompt and renders it until the prompt is
+// finished, the context is done or reading is interrupted. readline calls the
+// listener without a key each time that it starts to read, which is skipped so
+// that the prompt keeps the state left by the key that ended the last read. The
+// line reader is closed once reading stops, and the prompt is then torn down
+// under the lock since readline may still be handling the last key.
+func (lr *lineReader) readKeys(p keyPrompt) error {
+	sb := lr.ctx.newScreenBuf(lr)
+	p.render(sb)
+	handle := func(key rune) {
+		p.listen(key)
+		p.render(sb)
+		if p.finished() {
+			lr.stdin.Close()
+		}
+	}
+	if lr.hidden {
+		// readline stops reading after the keys that end a line until it has
+		// handled them, so they are passed on with the line left empty, as is
+		// the end of the input.
+		lr.filter = func(key rune) bool {
+			if key != 0 {
+				handle(key)
+			}
+			switch key {
+			case 0, readline.CharEnter, readline.CharCtrlJ, readline.CharInterrupt, readline.CharDelete:
+				return true
+			}
+			return false
+		}
+	} else {
+		lr.SetListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+			if key != 0 {
+				handle(key)
+			}
+			return nil, 0, true
+		})
+	}
+
+	var err error
+	for !lr.locked(p.finished) && err == nil && lr.ctx.Context().Err() == nil {
+		_, err = lr.Readline()
+		if err == io.EOF && !lr.ctx.keys().ended() {
+			err = nil
+		}
+	}
+	lr.Clean()
+	lr.Close()
+
+	lr.mut.Lock()
+	defer lr.mut.Unlock()
+	if lr.ctx.Context().Err() != nil {
+		sb.Clear()
+		return lr.ctx.Context().Err()
+	} else if cerr := p.cancel(interruption(err)); cerr != nil {
+		p.render(sb)
+		return cerr
+	}
+	return err
+}
+
+// Close stops watching the context and closes the readline instance, once it
+// has handled the last key that it read.
+func (lr *lineReader) Close() error {
+	lr.stop()
+	lr.stdin.Close()
+	err := lr.Instance.Close()
+	lr.stdin.idle()
+	return err
+}
+
+// cancelled erases the partial input of the line reader along with the lines
+// rendered above it and returns the context error. readline will submit any
+// partial input once its input is closed, so if the read did not fail the
+// submitted line is erased as well.
+func (ctx *Ctx) cancelled(lr *lineReader, readErr error, lines int) error {
+	lr.Clean()
+	if readErr == nil {
+		lines++
+	}
+	term.ClearLines(ctx.Writer(), lines)
+	return ctx.Context().Err()
+}
+
+// interruption maps the errors that readline returns when the user presses
+// Ctrl-C or Ctrl-D to ErrInterrupted and ErrAborted, any other error is not an
+// interruption and nil is returned.
+func interruption(err error) error {
+	if errors.Is(err, readline.ErrInterrupt) {
+		return ErrInterrupted
+	} else if errors.Is(err, io.EOF) {
+		return ErrAborted
+	}
+	return nil
+}
+
+// aborted erases the partial input of the line reader along with the lines
+// rendered above it, and marks the prompt as cancelled in their place.
+func (ctx *Ctx) aborted(lr *lineReader, label string, lines int) {
+	lr.Clean()
+	term.ClearLines(ctx.Writer(), lines)
+	ctx.markCancelled(label)
+}
+
+func (ctx *Ctx) markCancelled(label string) {
+	ctx.Println(Fmt(`{{iconBad}} {{.}} {{"(cancelled)" | faint}}`, label))
+}
+
+// inputLines is the amount of lines that readline used for a line of input
+// that it stopped reading. The input is ended with a new line unless the input
+// itself ended.
+func (ctx *Ctx) inputLines() int {
+	if ctx.keys().ended() {
+		return 0
+	}
+	return 1
+}
+
+// isTerminal is true if the input is a terminal. Readers that are not files are
+// treated as terminals, as documented by WithInput.
+func isTerminal(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	return !ok || readline.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,162 @@
+package gluey_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestZeroCtx(t *testing.T) {
+	// a zero Ctx reads stdin, which is replaced with an input that has ended
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	if (&gluey.Ctx{}).Writer() == nil {
+		t.Error("a zero Ctx has no writer")
+	}
+	ctx := &gluey.Ctx{Logger: log.New(io.Discard, "", 0)}
+	if _, _, err := ctx.Select("Pick", []string{"a", "b"}); err != nil && !errors.Is(err, gluey.ErrAborted) {
+		t.Errorf("Select = %v", err)
+	}
+	if _, err := ctx.Ask("Name?"); err != nil && !errors.Is(err, gluey.ErrAborted) {
+		t.Errorf("Ask = %v", err)
+	}
+}
+
+func TestWithAnswers(t *testing.T) {
+	answers := gluey.WithAnswers(gluey.AnswerMap{"Name?": "scripted"})
+
+	// a reader that is not a file is treated as a terminal
+	term := gluetest.NewTerminal()
+	term.Type("t", "y", "p", "e", "d", gluetest.KeyEnter)
+	if name, err := term.Ctx(answers).Ask("Name?"); err != nil || name != "typed" {
+		t.Errorf("Ask with a terminal = %q, %v", name, err)
+	}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	ctx := gluetest.NewTerminal().Ctx(gluey.WithInput(devNull), answers)
+	if name, err := ctx.Ask("Name?"); err != nil || name != "scripted" {
+		t.Errorf("Ask with a file = %q, %v", name, err)
+	}
+}
+
+func TestWithSize(t *testing.T) {
+	term := gluetest.NewTerminal()
+	done := make(chan error)
+	go func() {
+		_, _, err := term.Ctx(gluey.WithSize(80, 4)).Select("Pick", []string{"a", "b", "c", "d", "e"})
+		done <- err
+	}()
+	waitFor(t, term, "3  c")
+	if screen := term.Screen(); strings.Contains(screen, "4  d") {
+		t.Errorf("select is taller than the size:\n%s", screen)
+	}
+	term.Type(gluetest.KeyCtrlC)
+	<-done
+}
+
+func TestCancelContext(t *testing.T) {
+	prompts := map[string]func(*gluey.Ctx) error{
+		"Ask": func(ctx *gluey.Ctx) error {
+			_, err := ctx.Ask("Name?")
+			return err
+		},
+		"Select": func(ctx *gluey.Ctx) error {
+			_, _, err := ctx.Select("Pick", []string{"a", "b"})
+			return err
+		},
+		"Confirm": func(ctx *gluey.Ctx) error {
+			_, err := ctx.Confirm("Sure?")
+			return err
+		},
+	}
+	for name, prompt := range prompts {
+		t.Run(name, func(t *testing.T) {
+			term := gluetest.NewTerminal()
+			c, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			if err := prompt(term.Ctx().WithContext(c)); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("err = %v, want context.DeadlineExceeded", err)
+			}
+			if got := term.Screen(); got != "" {
+				t.Errorf("screen = %q, want it cleared", got)
+			}
+		})
+	}
+}
+
+func TestCancelledPrompts(t *testing.T) {
+	prompts := map[string]func(*gluey.Ctx) error{
+		"Ask": func(ctx *gluey.Ctx) error {
+			_, err := ctx.Ask("Name?")
+			return err
+		},
+		"Select": func(ctx *gluey.Ctx) error {
+			_, _, err := ctx.Select("Pick", []string{"a", "b"})
+			return err
+		},
+		"SelectMultiple": func(ctx *gluey.Ctx) error {
+			_, _, err := ctx.SelectMultiple("Pick", []string{"a", "b"})
+			return err
+		},
+		"AskMultiline": func(ctx *gluey.Ctx) error {
+			_, err := ctx.AskMultiline("Notes?")
+			return err
+		},
+		"Confirm": func(ctx *gluey.Ctx) error {
+			_, err := ctx.Confirm("Sure?")
+			return err
+		},
+	}
+	keys := []struct {
+		name string
+		keys []string
+		want error
+	}{
+		{"ctrl-c", []string{"x", gluetest.KeyCtrlC}, gluey.ErrInterrupted},
+		{"ctrl-d", []string{gluetest.KeyCtrlD}, gluey.ErrAborted},
+	}
+	for name, prompt := range prompts {
+		for _, key := range keys {
+			if name == "AskMultiline" && key.want == gluey.ErrAborted {
+				// Ctrl-D submits a multiline answer
+				continue
+			}
+			t.Run(name+" "+key.name, func(t *testing.T) {
+				term := gluetest.NewTerminal()
+				term.Type(key.keys...)
+				if err := prompt(term.Ctx()); err != key.want {
+					t.Errorf("err = %v, want %v", err, key.want)
+				}
+				if screen := term.Screen(); !strings.Contains(screen, "(cancelled)") {
+					t.Errorf("screen = %q, want it marked as cancelled", screen)
+				}
+			})
+		}
+		t.Run(name+" end of input", func(t *testing.T) {
+			term := gluetest.NewTerminal()
+			term.Close()
+			if err := prompt(term.Ctx()); err != gluey.ErrAborted {
+				t.Errorf("err = %v, want %v", err, gluey.ErrAborted)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package gluey
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileCompleter completes file paths, adding a path separator to directories.
+// Paths starting with ~ are completed from the home directory. AskFile reads
+// each directory once for the prompt, otherwise it is read for each completion.
+type FileCompleter struct {
+	// Base is the directory that relative paths are completed from, the current
+	// directory is used if it is empty.
+	Base string
+	// Extensions limits the files completed to those with one of the extensions,
+	// like ".go". Directories are still completed so that they can be entered.
+	Extensions []string
+	// DirsOnly only completes directories
+	DirsOnly bool
+	// HideHidden skips dotfiles unless the input starts their name with a dot
+	HideHidden bool
+	dirs       map[string][]os.DirEntry
+}
+
+// FileOption configures a file prompt
+type FileOption func(*filePrompt)
+
+type filePrompt struct {
+	completer    FileCompleter
+	mustExist    bool
+	mustNotExist bool
+}
+
+// AskFile will prompt the user for a filepath, completing paths as they type.
+// The path is returned with ~ expanded and joined to the base directory. Enter
+// on an empty input answers with an empty path, which is not validated.
+func (ctx *Ctx) AskFile(label string, opts ...FileOption) (string, error) {
+	fp := &filePrompt{completer: FileCompleter{dirs: map[string][]os.DirEntry{}}}
+	for _, opt := range opts {
+		opt(fp)
+	}
+	input, err := ctx.askComplete(label, fp.completer, fp.validate, true)
+	if err != nil || input == "" {
+		return "", err
+	}
+	return fp.completer.Path(input), nil
+}
+
+func AskFile(label string, opts ...FileOption) (string, error) {
+	return New().AskFile(label, opts...)
+}
+
+// WithBaseDir resolves relative paths from the directory instead of the current
+// directory.
+func WithBaseDir(dir string) FileOption {
+	return func(fp *filePrompt) {
+		fp.completer.Base = dir
+	}
+}
+
+// WithExtensions only accepts files with one of the extensions, like ".go"
+func WithExtensions(extensions ...string) FileOption {
+	return func(fp *filePrompt) {
+		fp.completer.Extensions = extensions
+	}
+}
+
+// WithDirsOnly only accepts directories that exist, or that do not exist yet
+// along with WithMustNotExist.
+func WithDirsOnly() FileOption {
+	return func(fp *filePrompt) {
+		fp.completer.DirsOnly = true
+	}
+}
+
+// WithoutHidden does not complete dotfiles unless the user starts typing one
+func WithoutHidden() FileOption {
+	return func(fp *filePrompt) {
+		fp.completer.HideHidden = true
+	}
+}
+
+// WithMustExist only accepts paths that exist, like input files
+func WithMustExist() FileOption {
+	return func(fp *filePrompt) {
+		fp.mustExist = true
+	}
+}
+
+// WithMustNotExist only accepts paths that do not exist yet, like output files
+func WithMustNotExist() FileOption {
+	return func(fp *filePrompt) {
+		fp.mustNotExist = true
+	}
+}
+
+func (fp *filePrompt) validate(input string) error {
+	if input == "" {
+		return nil
+	}
+	fc := fp.completer
+	info, err := os.Stat(fc.Path(input))
+	isDir := err == nil && info.IsDir()
+	switch {
+	case (fp.mustExist || fc.DirsOnly && !fp.mustNotExist) && err != nil:
+		return fmt.Errorf("%v does not exist", input)
+	case fp.mustNotExist && err == nil:
+		return fmt.Errorf("%v already exists", input)
+	case fc.DirsOnly && err == nil && !isDir:
+		return fmt.Errorf("%v is not a directory", input)
+	case !fc.DirsOnly && !isDir && !fc.hasExtension(input):
+		return fmt.Errorf("must have the extension %v", strings.Join(fc.extensions(), " or "))
+	}
+	return nil
+}
+
+// Complete returns the paths that start with the input
+func (fc FileCompleter) Complete(input string) []string {
+	if input == "~" {
+		return []string{input + string(os.PathSeparator)}
+	}
+	_, typed := filepath.Split(input)
+	dir, _ := filepath.Split(fc.Path(input))
+	candidates := []string{}
+	for _, entry := range fc.list(dir) {
+		name := entry.Name()
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(filepath.Join(dir, name))
+			isDir = err == nil && info.IsDir()
+		}
+		if !strings.HasPrefix(name, typed) ||
+			fc.HideHidden && strings.HasPrefix(name, ".") && !strings.HasPrefix(typed, ".") ||
+			!isDir && (fc.DirsOnly || !fc.hasExtension(name)) {
+			continue
+		}
+		candidate := input + strings.TrimPrefix(name, typed)
+		if isDir {
+			candidate += string(os.PathSeparator)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// list reads the entries of the directory, only once when the completer keeps
+// the directories that it has read.
+func (fc FileCompleter) list(dir string) []os.DirEntry {
+	if entries, ok := fc.dirs[dir]; ok {
+		return entries
+	}
+	entries, _ := os.ReadDir(cmp.Or(dir, "."))
+	if fc.dirs != nil {
+		fc.dirs[dir] = entries
+	}
+	return entries
+}
+
+// Path resolves the input to the path that it refers to, expanding ~ to the
+// home directory and joining relative paths to the base directory.
+func (fc FileCompleter) Path(input string) string {
+	if input == "~" || len(input) > 1 && input[0] == '~' && os.IsPathSeparator(input[1]) {
+		if home, err := os.UserHomeDir(); err == nil {
+			input = home + input[1:]
+		}
+	}
+	if fc.Base != "" && !filepath.IsAbs(input) {
+		input = strings.TrimRight(fc.Base, string(os.PathSeparator)) + string(os.PathSeparator) + input
+	}
+	return input
+}
+
+func (fc FileCompleter) hasExtension(name string) bool {
+	if len(fc.Extensions) == 0 {
+		return true
+	}
+	for _, extension := range fc.extensions() {
+		if strings.EqualFold(filepath.Ext(name), extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// extensions lists the extensions with their leading dot, which is optional
+func (fc FileCompleter) extensions() []string {
+	extensions := make([]string, len(fc.Extensions))
+	for i, extension := range fc.Extensions {
+		extensions[i] = "." + strings.TrimPrefix(extension, ".")
+	}
+	return extensions
+}
@@ -0,0 +1,131 @@
+package gluey_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+// testDir makes a directory with the files, where names ending with a separator
+// are made as directories.
+func testDir(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		var err error
+		if strings.HasSuffix(name, "/") {
+			err = os.MkdirAll(path, 0o755)
+		} else {
+			err = os.WriteFile(path, nil, 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFileCompleter(t *testing.T) {
+	dir := testDir(t, "a[1].txt", "a*.go", "abc.go", "sub/", ".hidden")
+	sep := string(os.PathSeparator)
+	cases := []struct {
+		name      string
+		completer gluey.FileCompleter
+		input     string
+		want      []string
+	}{
+		{"all", gluey.FileCompleter{Base: dir}, "", []string{".hidden", "a*.go", "a[1].txt", "abc.go", "sub" + sep}},
+		{"glob characters are literal", gluey.FileCompleter{Base: dir}, "a[", []string{"a[1].txt"}},
+		{"star is literal", gluey.FileCompleter{Base: dir}, "a*", []string{"a*.go"}},
+		{"extensions", gluey.FileCompleter{Base: dir, Extensions: []string{"go"}}, "", []string{"a*.go", "abc.go", "sub" + sep}},
+		{"dirs only", gluey.FileCompleter{Base: dir, DirsOnly: true}, "", []string{"sub" + sep}},
+		{"hide hidden", gluey.FileCompleter{Base: dir, HideHidden: true}, "", []string{"a*.go", "a[1].txt", "abc.go", "sub" + sep}},
+		{"typed hidden", gluey.FileCompleter{Base: dir, HideHidden: true}, ".", []string{".hidden"}},
+		{"absolute", gluey.FileCompleter{}, dir + sep + "ab", []string{dir + sep + "abc.go"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.completer.Complete(c.input); !slices.Equal(got, c.want) {
+				t.Errorf("Complete(%q) = %q, want %q", c.input, got, c.want)
+			}
+		})
+	}
+}
+
+func TestAskFile(t *testing.T) {
+	dir := testDir(t, "main.go", "notes.txt", "sub/")
+	cases := []struct {
+		name string
+		opts []gluey.FileOption
+		keys []string
+		want string
+		err  string
+	}{
+		{"completes", nil, []string{"ma", gluetest.KeyTab, gluetest.KeyEnter}, "main.go", ""},
+		{"empty", []gluey.FileOption{gluey.WithMustExist()}, []string{gluetest.KeyEnter}, "", ""},
+		{"must exist", []gluey.FileOption{gluey.WithMustExist()}, []string{"x", gluetest.KeyEnter}, "", "x does not exist"},
+		{"must not exist", []gluey.FileOption{gluey.WithMustNotExist()}, []string{"main.go", gluetest.KeyEnter}, "", "main.go already exists"},
+		{"dirs only", []gluey.FileOption{gluey.WithDirsOnly()}, []string{"main.go", gluetest.KeyEnter}, "", "main.go is not a directory"},
+		{"dirs only must exist", []gluey.FileOption{gluey.WithDirsOnly()}, []string{"new", gluetest.KeyEnter}, "", "new does not exist"},
+		{"new dir", []gluey.FileOption{gluey.WithDirsOnly(), gluey.WithMustNotExist()}, []string{"new", gluetest.KeyEnter}, "new", ""},
+		{"extensions", []gluey.FileOption{gluey.WithExtensions(".go")}, []string{"notes.txt", gluetest.KeyEnter}, "", "must have the extension .go"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := gluetest.NewTerminal()
+			term.Type(c.keys...)
+			if c.err != "" {
+				// the answer is refused, so the prompt is left open until it is
+				// interrupted
+				done := make(chan error)
+				go func() {
+					_, err := term.Ctx().AskFile("File?", append(c.opts, gluey.WithBaseDir(dir))...)
+					done <- err
+				}()
+				waitFor(t, term, c.err)
+				term.Type(gluetest.KeyCtrlC)
+				if err := <-done; err != gluey.ErrInterrupted {
+					t.Errorf("AskFile = %v", err)
+				}
+				return
+			}
+			want := c.want
+			if want != "" {
+				want = filepath.Join(dir, want)
+			}
+			if got, err := term.Ctx().AskFile("File?", append(c.opts, gluey.WithBaseDir(dir))...); err != nil || got != want {
+				t.Errorf("AskFile = %q, %v, want %q", got, err, want)
+			}
+		})
+	}
+}
+
+func TestAskFileReadsDirectoriesOnce(t *testing.T) {
+	dir := testDir(t, "one.txt")
+	term := gluetest.NewTerminal()
+	done := make(chan string)
+	go func() {
+		path, _ := term.Ctx().AskFile("File?", gluey.WithBaseDir(dir))
+		done <- path
+	}()
+	term.Type("o")
+	waitFor(t, term, "one.txt")
+	if err := os.WriteFile(filepath.Join(dir, "other.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	term.Type(gluetest.KeyBackspace, "o", "t")
+	waitFor(t, term, "> ot")
+	if strings.Contains(term.Screen(), "other.txt") {
+		t.Errorf("directory was read again:\n%s", term.Screen())
+	}
+	term.Type(gluetest.KeyEnter)
+	if path := <-done; path != filepath.Join(dir, "ot") {
+		t.Errorf("AskFile = %q", path)
+	}
+}
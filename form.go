@@ -0,0 +1,250 @@
+package gluey
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// formValidators are the validators that can be named in a validate tag
+var formValidators = map[string]Validator{
+	"notempty": NotEmpty,
+	"url":      URL,
+	"path":     PathExists,
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// RegisterValidator makes a validator available to the validate tag of Form
+// fields by name. It should be called before any forms are run.
+func RegisterValidator(name string, validate Validator) {
+	formValidators[name] = validate
+}
+
+// Form will prompt for each exported field of the struct that dest points to,
+// and fill it in with the answer. Fields are configured with the tags:
+//
+//	label:    the prompt label, defaults to the field name. "-" skips the field
+//	default:  the default answer, defaults to the current value of the field.
+//	          comma separated for []string fields
+//	options:  comma separated options to select from for string and []string fields
+//	required: "true" re-prompts on empty answers
+//	secret:   "true" hides the input of a string field
+//	validate: comma separated names of validators, see RegisterValidator
+//	layout:   the layout of a time.Time field, defaults to time.DateOnly
+//
+// string fields are asked for, bool fields are confirmed, int, float,
+// time.Duration and time.Time fields use the typed asks and nested structs are
+// asked in order.
+func (ctx *Ctx) Form(dest any) error {
+	val := reflect.ValueOf(dest)
+	if val.Kind() != reflect.Pointer || val.Elem().Kind() != reflect.Struct {
+		return errors.New("form destination must be a pointer to a struct")
+	}
+	return ctx.form(val.Elem())
+}
+
+func Form(dest any) error {
+	return New().Form(dest)
+}
+
+func (ctx *Ctx) form(val reflect.Value) error {
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		if !field.IsExported() || field.Tag.Get("label") == "-" {
+			continue
+		}
+		if err := ctx.formField(field, val.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ctx *Ctx) formField(field reflect.StructField, val reflect.Value) error {
+	label := field.Tag.Get("label")
+	if label == "" {
+		label = field.Name
+	}
+	layout := field.Tag.Get("layout")
+	if layout == "" {
+		layout = time.DateOnly
+	}
+	dflt, hasDefault := field.Tag.Lookup("default")
+	if !hasDefault && !val.IsZero() {
+		if t, ok := val.Interface().(time.Time); ok {
+			dflt = t.Format(layout)
+		} else {
+			dflt = fmt.Sprint(val.Interface())
+		}
+	}
+	required := field.Tag.Get("required") == "true"
+	validate, err := formValidator(field.Tag.Get("validate"), required)
+	if err != nil {
+		return err
+	}
+	var options []string
+	if opts := field.Tag.Get("options"); opts != "" {
+		options = strings.Split(opts, ",")
+	}
+
+	switch {
+	case val.Type() == durationType:
+		d, err := askParsed(ctx, label, dflt, parseDuration(math.MinInt64, math.MaxInt64))
+		if err != nil {
+			return err
+		}
+		val.SetInt(int64(d))
+		return nil
+	case val.Type() == timeType:
+		t, err := askParsed(ctx, label, dflt, parseDate(layout, time.Time{}, time.Time{}))
+		if err != nil {
+			return err
+		}
+		val.Set(reflect.ValueOf(t))
+		return nil
+	case val.Kind() == reflect.String && len(options) > 0:
+		opts := []SelectOption{}
+		if i := slices.Index(options, dflt); i >= 0 {
+			opts = append(opts, WithDefault(i+1))
+		}
+		for {
+			_, choice, err := ctx.Select(label, options, opts...)
+			if err != nil {
+				return err
+			} else if verr := validate(choice); verr != nil {
+				if err := ctx.formInvalid(label, choice, verr); err != nil {
+					return err
+				}
+				continue
+			}
+			val.SetString(choice)
+			return nil
+		}
+	case val.Kind() == reflect.String && field.Tag.Get("secret") == "true":
+		for {
+			input, err := ctx.AskPassword(label)
+			if err != nil {
+				return err
+			} else if verr := validate(input); verr != nil {
+				if err := ctx.formInvalid(label, input, verr); err != nil {
+					return err
+				}
+				continue
+			}
+			val.SetString(input)
+			return nil
+		}
+	case val.Kind() == reflect.String:
+		input, err := ctx.askValid(label, dflt, validate)
+		if err != nil {
+			return err
+		}
+		val.SetString(input)
+		return nil
+	case val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.String && len(options) > 0:
+		checked := []int{}
+		defaults := formDefaults(val, dflt, hasDefault)
+		for i, option := range options {
+			if slices.Contains(defaults, option) {
+				checked = append(checked, i+1)
+			}
+		}
+		for {
+			_, choices, err := ctx.SelectMultiple(label, options, WithChecked(checked...))
+			if err != nil {
+				return err
+			} else if required && len(choices) == 0 {
+				if err := ctx.formInvalid(label, "", errors.New("must choose at least one")); err != nil {
+					return err
+				}
+				continue
+			}
+			val.Set(reflect.ValueOf(choices).Convert(val.Type()))
+			return nil
+		}
+	case val.Kind() == reflect.Bool:
+		yes, err := ctx.ConfirmSelect(label, dflt == "true")
+		if err != nil {
+			return err
+		}
+		val.SetBool(yes)
+		return nil
+	case val.CanInt():
+		maxVal := intMax(uint64(math.MaxInt64) >> (64 - val.Type().Bits()))
+		n, err := askParsed(ctx, label, dflt, parseInt(-maxVal-1, maxVal))
+		if err != nil {
+			return err
+		}
+		val.SetInt(int64(n))
+		return nil
+	case val.CanUint():
+		n, err := askParsed(ctx, label, dflt, parseInt(0, intMax(math.MaxUint64>>(64-val.Type().Bits()))))
+		if err != nil {
+			return err
+		}
+		val.SetUint(uint64(n))
+		return nil
+	case val.CanFloat():
+		n, err := askParsed(ctx, label, dflt, parseFloat(-math.MaxFloat64, math.MaxFloat64))
+		if err != nil {
+			return err
+		}
+		val.SetFloat(n)
+		return nil
+	case val.Kind() == reflect.Struct:
+		return ctx.form(val)
+	}
+	return fmt.Errorf("form field %v has unsupported type %v", field.Name, field.Type)
+}
+
+// formInvalid shows why an answer is invalid so that it can be asked again, or
+// returns an error if the form is not interactive and can not ask again.
+func (ctx *Ctx) formInvalid(label, answer string, err error) error {
+	if ctx.nonInteractive {
+		return &AnswerError{Label: label, Answer: answer, Err: err}
+	}
+	ctx.Println(Fmt(`{{iconBad}} {{. | red}}`, err))
+	return nil
+}
+
+func formValidator(names string, required bool) (Validator, error) {
+	validators := []Validator{}
+	if required {
+		validators = append(validators, NotEmpty)
+	}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		} else if validate, ok := formValidators[name]; ok {
+			validators = append(validators, validate)
+		} else {
+			return nil, fmt.Errorf("unknown validator %q", name)
+		}
+	}
+	return All(validators...), nil
+}
+
+// formDefaults are the default choices of a []string field, from the default
+// tag or the current value of the field.
+func formDefaults(val reflect.Value, dflt string, hasDefault bool) []string {
+	if hasDefault {
+		return strings.Split(dflt, ",")
+	}
+	return val.Convert(reflect.TypeOf([]string{})).Interface().([]string)
+}
+
+// intMax caps the maximum value of an integer type to what fits in an int
+func intMax(maxVal uint64) int {
+	if maxVal > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(maxVal)
+}
@@ -0,0 +1,102 @@
+package gluey_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+type deployForm struct {
+	Name     string        `label:"Name?" validate:"notempty"`
+	Target   string        `label:"Target?" options:"staging,production"`
+	Regions  []string      `label:"Regions?" options:"us,eu,ap" default:"us"`
+	Replicas int           `label:"Replicas?"`
+	Timeout  time.Duration `label:"Timeout?" default:"30s"`
+	Date     time.Time     `label:"Date?"`
+	Canary   bool          `label:"Canary?"`
+	Skipped  string        `label:"-"`
+	Nested   struct {
+		Owner string `label:"Owner?"`
+	}
+}
+
+func TestForm(t *testing.T) {
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{
+		"Name?":     "api",
+		"Target?":   "production",
+		"Regions?":  "us,eu",
+		"Replicas?": "3",
+		"Date?":     "2024-05-01",
+		"Canary?":   "yes",
+		"Owner?":    "ops",
+	}))
+	var form deployForm
+	if err := ctx.Form(&form); err != nil {
+		t.Fatal(err)
+	}
+	if form.Name != "api" || form.Target != "production" || !slices.Equal(form.Regions, []string{"us", "eu"}) ||
+		form.Replicas != 3 || form.Timeout != 30*time.Second || !form.Canary || form.Nested.Owner != "ops" {
+		t.Errorf("form = %+v", form)
+	}
+	if want := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC); !form.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", form.Date, want)
+	}
+}
+
+func TestFormTimeDefault(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyEnter)
+	form := struct {
+		Date time.Time `label:"Date?" layout:"02/01/2006"`
+	}{Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+	if err := term.Ctx().Form(&form); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC); !form.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", form.Date, want)
+	}
+	waitFor(t, term, "[default = 01/05/2024]")
+}
+
+func TestFormRequiredSelect(t *testing.T) {
+	form := struct {
+		Target string `label:"Target?" options:",staging" required:"true"`
+	}{}
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Target?": "1"}))
+	var answerErr *gluey.AnswerError
+	if err := ctx.Form(&form); !errors.As(err, &answerErr) {
+		t.Errorf("Form = %v, want an AnswerError", err)
+	}
+
+	term := gluetest.NewTerminal()
+	term.Type("1", "2")
+	if err := term.Ctx().Form(&form); err != nil || form.Target != "staging" {
+		t.Errorf("Form = %q, %v", form.Target, err)
+	}
+	waitFor(t, term, "must not be empty")
+}
+
+func TestFormRequiredSelectMultiple(t *testing.T) {
+	form := struct {
+		Regions []string `label:"Regions?" options:"us,eu" required:"true"`
+	}{}
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Regions?": ""}))
+	var answerErr *gluey.AnswerError
+	if err := ctx.Form(&form); !errors.As(err, &answerErr) {
+		t.Errorf("Form = %v, want an AnswerError", err)
+	}
+}
+
+func TestFormUnsupported(t *testing.T) {
+	form := struct{ Ch chan int }{}
+	if err := gluetest.NewTerminal().Ctx().Form(&form); err == nil {
+		t.Error("Form accepted a chan field")
+	}
+	if err := gluetest.NewTerminal().Ctx().Form(form); err == nil {
+		t.Error("Form accepted a struct that is not a pointer")
+	}
+}
@@ -5,8 +5,6 @@ import (
 	"log"
 	"strings"
 	"time"
-
-	"github.com/tanema/gluey/term"
 )
 
 // FrameFunc is the function call that is called inside the frame
@@ -39,8 +37,9 @@ func InFrame(title string, fn FrameFunc) error {
 }
 
 func newFrame(ctx *Ctx) *Frame {
-	nestedCtx := &Ctx{Indent: ctx.Indent + 2}
-	frame := &Frame{ctx: ctx, nestedCtx: nestedCtx}
+	nestedCtx := *ctx
+	nestedCtx.Indent = ctx.Indent + 2
+	frame := &Frame{ctx: ctx, nestedCtx: &nestedCtx}
 	frame.SetColor("cyan")
 	return frame
 }
@@ -97,7 +96,7 @@ func (frame *Frame) bar(bt barType, left, right string) string {
 	if len(right) > 0 {
 		right = " " + strings.TrimSpace(right) + " "
 	}
-	padding := term.Width() - len(prefix) - len(left) - len(right) - (frame.ctx.Indent - 2)
+	padding := frame.ctx.width() - len(prefix) - len(left) - len(right) - (frame.ctx.Indent - 2)
 	bar := strings.Repeat("━", padding)
 	return Fmt("{{ . | "+frame.color+" }}", prefix+left+bar+right)
 }
@@ -0,0 +1,19 @@
+package gluetest_test
+
+import (
+	"testing"
+
+	"github.com/tanema/gluey/gluetest"
+)
+
+// TestReadme runs the example from the README
+func TestReadme(t *testing.T) {
+	term := gluetest.NewTerminal()
+	ctx := term.Ctx()
+	term.Type(gluetest.KeyDown, gluetest.KeyEnter)
+	_, hardness, err := ctx.Select("How hard?", []string{"Easy", "Hard"})
+	if err != nil || hardness != "Hard" {
+		t.Fatalf("Select = %q, %v", hardness, err)
+	}
+	gluetest.AssertGolden(t, term, "testdata/select.golden")
+}
@@ -0,0 +1,35 @@
+package gluetest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateEnv is the environment variable that, when set to a non empty value,
+// makes AssertGolden write the current screen to the golden file instead of
+// comparing against it.
+const UpdateEnv = "GLUETEST_UPDATE"
+
+// AssertGolden compares the screen of the terminal to the contents of the golden
+// file at path, failing the test if they differ.
+func AssertGolden(tb testing.TB, t *Terminal, path string) {
+	tb.Helper()
+	screen := t.Screen() + "\n"
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(screen), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+		return
+	}
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("could not read golden file, run with %s=1 to create it: %v", UpdateEnv, err)
+	}
+	if string(golden) != screen {
+		tb.Errorf("screen does not match %s\n--- want\n%s\n--- got\n%s", path, golden, screen)
+	}
+}
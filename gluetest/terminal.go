@@ -0,0 +1,275 @@
+// Package gluetest provides a virtual terminal for testing gluey prompts and
+// renderers. The terminal interprets the ANSI escapes that gluey and readline
+// write so that tests can assert on what a user would actually see.
+package gluetest
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/tanema/gluey"
+)
+
+// Keys that can be passed to Terminal.Type
+const (
+	KeyEnter     = "\r"
+	KeyTab       = "\t"
+	KeyBackspace = "\x7f"
+	KeyEsc       = "\x1b"
+	KeyUp        = "\x1b[A"
+	KeyDown      = "\x1b[B"
+	KeyRight     = "\x1b[C"
+	KeyLeft      = "\x1b[D"
+	KeyCtrlC     = "\x03"
+	KeyCtrlD     = "\x04"
+)
+
+const (
+	defaultWidth  = 80
+	defaultHeight = 60
+)
+
+// Terminal is a fake terminal that can be used as both the input and output of
+// a gluey.Ctx. Output is drawn onto a grid of cells, the grid grows downward as
+// lines are written so the scrollback is never lost.
+type Terminal struct {
+	mut     sync.Mutex
+	cond    *sync.Cond
+	width   int
+	height  int
+	lines   [][]rune
+	row     int
+	col     int
+	pending []byte
+	keys    []string
+	closed  bool
+}
+
+// NewTerminal creates a terminal that is 80 columns wide and 60 rows high
+func NewTerminal() *Terminal {
+	return NewTerminalSize(defaultWidth, defaultHeight)
+}
+
+// NewTerminalSize creates a terminal with a set size
+func NewTerminalSize(width, height int) *Terminal {
+	t := &Terminal{width: width, height: height, lines: [][]rune{{}}}
+	t.cond = sync.NewCond(&t.mut)
+	return t
+}
+
+// Ctx creates a new gluey context that reads from and writes to the terminal
+func (t *Terminal) Ctx(opts ...gluey.Option) *gluey.Ctx {
+	return gluey.New(append([]gluey.Option{
+		gluey.WithInput(t),
+		gluey.WithOutput(t),
+		gluey.WithErrOutput(t),
+		gluey.WithSize(t.width, t.height),
+	}, opts...)...)
+}
+
+// Type queues keys to be read by the prompts. Each key is handed out by a
+// separate read so that a prompt will not consume keys meant for the next
+// prompt, which allows keys for several prompts to be typed up front.
+func (t *Terminal) Type(keys ...string) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	for _, key := range keys {
+		if key != "" {
+			t.keys = append(t.keys, key)
+		}
+	}
+	t.cond.Broadcast()
+}
+
+// Read will block until a key has been typed or the terminal is closed
+func (t *Terminal) Read(p []byte) (int, error) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	for len(t.keys) == 0 && !t.closed {
+		t.cond.Wait()
+	}
+	if len(t.keys) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, t.keys[0])
+	if n < len(t.keys[0]) {
+		t.keys[0] = t.keys[0][n:]
+	} else {
+		t.keys = t.keys[1:]
+	}
+	return n, nil
+}
+
+// Close will make any pending and future reads return io.EOF
+func (t *Terminal) Close() error {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	t.closed = true
+	t.cond.Broadcast()
+	return nil
+}
+
+// Write interprets the output and draws it onto the screen
+func (t *Terminal) Write(p []byte) (int, error) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	t.pending = append(t.pending, p...)
+	for len(t.pending) > 0 {
+		n := t.interpret(t.pending)
+		if n == 0 {
+			break
+		}
+		t.pending = t.pending[n:]
+	}
+	return len(p), nil
+}
+
+// Lines returns each line on the screen with trailing whitespace removed.
+// Trailing empty lines are not included.
+func (t *Terminal) Lines() []string {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	lines := make([]string, len(t.lines))
+	for i, line := range t.lines {
+		lines[i] = strings.TrimRight(string(line), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Screen returns the text on the screen, with all styling removed.
+func (t *Terminal) Screen() string {
+	return strings.Join(t.Lines(), "\n")
+}
+
+// Cursor returns the current row and column of the cursor
+func (t *Terminal) Cursor() (row, col int) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	return t.row, t.col
+}
+
+// interpret will handle a single character or escape sequence from the start
+// of b and return how many bytes it used. If b is an incomplete sequence 0 is
+// returned so that the rest can be waited for.
+func (t *Terminal) interpret(b []byte) int {
+	switch b[0] {
+	case '\x1b':
+		return t.escape(b)
+	case '\r':
+		t.col = 0
+	case '\n':
+		t.moveTo(t.row+1, 0)
+	case '\b':
+		t.col = max(t.col-1, 0)
+	case '\t':
+		t.col = min((t.col/8+1)*8, t.width-1)
+	default:
+		if !utf8.FullRune(b) {
+			return 0
+		}
+		r, size := utf8.DecodeRune(b)
+		if r >= ' ' {
+			t.put(r)
+		}
+		return size
+	}
+	return 1
+}
+
+func (t *Terminal) escape(b []byte) int {
+	if len(b) < 2 {
+		return 0
+	} else if b[1] != '[' {
+		return 2
+	}
+	for i := 2; i < len(b); i++ {
+		if b[i] >= '@' && b[i] <= '~' {
+			t.csi(string(b[2:i]), b[i])
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (t *Terminal) csi(params string, final byte) {
+	if strings.HasPrefix(params, "?") {
+		return
+	}
+	args := strings.Split(params, ";")
+	arg := func(i, dflt int) int {
+		if i >= len(args) {
+			return dflt
+		}
+		n, err := strconv.Atoi(args[i])
+		if err != nil {
+			return dflt
+		}
+		return n
+	}
+	switch final {
+	case 'A':
+		t.moveTo(t.row-max(arg(0, 1), 1), t.col)
+	case 'B':
+		t.moveTo(t.row+max(arg(0, 1), 1), t.col)
+	case 'C':
+		t.col = min(t.col+max(arg(0, 1), 1), t.width-1)
+	case 'D':
+		t.col = max(t.col-max(arg(0, 1), 1), 0)
+	case 'G':
+		t.col = clamp(arg(0, 1)-1, 0, t.width-1)
+	case 'H':
+		t.moveTo(arg(0, 1)-1, clamp(arg(1, 1)-1, 0, t.width-1))
+	case 'K':
+		line := t.lines[t.row]
+		switch arg(0, 0) {
+		case 0:
+			t.lines[t.row] = line[:min(t.col, len(line))]
+		case 1:
+			for i := 0; i <= t.col && i < len(line); i++ {
+				line[i] = ' '
+			}
+		case 2:
+			t.lines[t.row] = []rune{}
+		}
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			t.lines[t.row] = t.lines[t.row][:min(t.col, len(t.lines[t.row]))]
+			t.lines = t.lines[:t.row+1]
+		case 2, 3:
+			t.lines = [][]rune{{}}
+			t.row, t.col = 0, 0
+		}
+	}
+}
+
+func (t *Terminal) put(r rune) {
+	if t.col >= t.width {
+		t.moveTo(t.row+1, 0)
+	}
+	line := t.lines[t.row]
+	for len(line) <= t.col {
+		line = append(line, ' ')
+	}
+	line[t.col] = r
+	t.lines[t.row] = line
+	t.col++
+}
+
+func (t *Terminal) moveTo(row, col int) {
+	t.row = max(row, 0)
+	t.col = col
+	for len(t.lines) <= t.row {
+		t.lines = append(t.lines, []rune{})
+	}
+}
+
+func clamp(a, minVal, maxVal int) int {
+	return max(min(a, maxVal), minVal)
+}
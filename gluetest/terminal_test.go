@@ -0,0 +1,182 @@
+package gluetest
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tanema/gluey/term"
+)
+
+func TestWriteText(t *testing.T) {
+	term := NewTerminal()
+	io.WriteString(term, "hello\nworld\r\nagain  \n\n")
+	if got, want := term.Screen(), "hello\nworld\nagain"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+	if row, col := term.Cursor(); row != 4 || col != 0 {
+		t.Errorf("cursor = %v,%v, want 4,0", row, col)
+	}
+}
+
+func TestWriteWraps(t *testing.T) {
+	term := NewTerminalSize(4, 10)
+	io.WriteString(term, "abcdefg")
+	if got, want := term.Lines(), []string{"abcd", "efg"}; !slices.Equal(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestWriteStripsStyles(t *testing.T) {
+	term := NewTerminal()
+	io.WriteString(term, "\x1b[31mred\x1b[0m \x1b[1;4mbold\x1b[0m\x1b[?25l")
+	if got, want := term.Screen(), "red bold"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestWriteSplitSequences(t *testing.T) {
+	term := NewTerminal()
+	for _, part := range []string{"ab", "\x1b", "[", "1D", "\xc3", "\xa9"} {
+		io.WriteString(term, part)
+	}
+	if got, want := term.Screen(), "aé"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestCSI(t *testing.T) {
+	cases := []struct {
+		name, out, want string
+	}{
+		{"up", "one\ntwo\x1b[1Ax", "onex\ntwo"},
+		{"down", "one\x1b[2Bx", "one\n\n   x"},
+		{"forward", "a\x1b[3Cb", "a   b"},
+		{"back", "abcd\x1b[2Dx", "abxd"},
+		{"column", "abcd\x1b[2Gx", "axcd"},
+		{"position", "one\ntwo\x1b[1;2Hx", "oxe\ntwo"},
+		{"erase to end of line", "abcd\x1b[2D\x1b[0K", "ab"},
+		{"erase to start of line", "abcd\x1b[2D\x1b[1K", "   d"},
+		{"erase line", "abcd\x1b[2K", ""},
+		{"erase below", "one\ntwo\nthree\x1b[2A\x1b[2G\x1b[0J", "o"},
+		{"erase screen", "one\ntwo\x1b[2Jx", "x"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := NewTerminal()
+			io.WriteString(term, c.out)
+			if got := term.Screen(); got != c.want {
+				t.Errorf("screen = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestClearLines(t *testing.T) {
+	tm := NewTerminal()
+	io.WriteString(tm, "keep\none\ntwo\n")
+	term.ClearLines(tm, 2)
+	io.WriteString(tm, "new\n")
+	if got, want := tm.Screen(), "keep\nnew"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestScreenBuf(t *testing.T) {
+	tm := NewTerminal()
+	sb := term.NewScreenBuf(tm)
+	sb.WriteTmpl("{{.}}\nsecond", "first")
+	sb.WriteTmpl("{{.}}", "replaced")
+	if got, want := tm.Screen(), "replaced"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+	sb.Clear()
+	if got := tm.Screen(); got != "" {
+		t.Errorf("screen = %q, want it cleared", got)
+	}
+}
+
+func TestTypeHandsOutKeysSeparately(t *testing.T) {
+	term := NewTerminal()
+	term.Type("ab", "", KeyUp)
+	buf := make([]byte, 16)
+	for _, want := range []string{"ab", KeyUp} {
+		n, err := term.Read(buf)
+		if err != nil || string(buf[:n]) != want {
+			t.Errorf("read %q, %v, want %q", buf[:n], err, want)
+		}
+	}
+}
+
+func TestReadPartialKey(t *testing.T) {
+	term := NewTerminal()
+	term.Type(KeyUp)
+	buf := make([]byte, 2)
+	var got []byte
+	for range 2 {
+		n, _ := term.Read(buf)
+		got = append(got, buf[:n]...)
+	}
+	if string(got) != KeyUp {
+		t.Errorf("read %q, want %q", got, KeyUp)
+	}
+}
+
+func TestReadWaitsForKeys(t *testing.T) {
+	term := NewTerminal()
+	read := make(chan string)
+	go func() {
+		buf := make([]byte, 16)
+		n, _ := term.Read(buf)
+		read <- string(buf[:n])
+	}()
+	term.Type("x")
+	if got := <-read; got != "x" {
+		t.Errorf("read %q, want x", got)
+	}
+}
+
+func TestCloseEndsReads(t *testing.T) {
+	term := NewTerminal()
+	term.Type("x")
+	term.Close()
+	buf := make([]byte, 16)
+	if n, err := term.Read(buf); err != nil || string(buf[:n]) != "x" {
+		t.Errorf("read %q, %v, want the typed key first", buf[:n], err)
+	}
+	if _, err := term.Read(buf); err != io.EOF {
+		t.Errorf("read error = %v, want io.EOF", err)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	term := NewTerminal()
+	io.WriteString(term, "golden\nscreen")
+	path := filepath.Join(t.TempDir(), "testdata", "screen.golden")
+
+	t.Setenv(UpdateEnv, "1")
+	AssertGolden(t, term, path)
+	if golden, err := os.ReadFile(path); err != nil || string(golden) != "golden\nscreen\n" {
+		t.Fatalf("golden file = %q, %v", golden, err)
+	}
+
+	t.Setenv(UpdateEnv, "")
+	AssertGolden(t, term, path)
+	fake := &fakeTB{TB: t}
+	io.WriteString(term, " changed")
+	AssertGolden(fake, term, path)
+	if !fake.failed {
+		t.Error("AssertGolden passed on a changed screen")
+	}
+}
+
+// fakeTB records failures instead of failing the test
+type fakeTB struct {
+	testing.TB
+	failed bool
+}
+
+func (tb *fakeTB) Errorf(string, ...any) { tb.failed = true }
+func (tb *fakeTB) Fatalf(string, ...any) { tb.failed = true }
@@ -0,0 +1,31 @@
+package gluey_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanema/gluey/gluetest"
+)
+
+// waitFor waits for the text to be shown on the screen of the terminal
+func waitFor(t *testing.T, term *gluetest.Terminal, text string) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if strings.Contains(term.Screen(), text) {
+			return
+		}
+	}
+	t.Fatalf("%q was not shown, screen:\n%s", text, term.Screen())
+}
+
+// waitForScreen waits for the screen of the terminal to be exactly the text
+func waitForScreen(t *testing.T, term *gluetest.Terminal, text string) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if term.Screen() == text {
+			return
+		}
+	}
+	t.Fatalf("screen is not %q:\n%s", text, term.Screen())
+}
@@ -0,0 +1,88 @@
+package gluey
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/chzyer/readline"
+)
+
+// historyLimit is the default amount of entries kept in each prompt's history
+const historyLimit = 100
+
+// WithHistory saves what is typed into text prompts so that it can be recalled
+// with ↑ or searched with Ctrl-R the next time the prompt is asked. Each prompt
+// has its own history, keyed by its label, saved under the user's cache
+// directory in a directory named after the app.
+func WithHistory(app string) Option {
+	return func(ctx *Ctx) {
+		if dir, err := os.UserCacheDir(); err == nil {
+			ctx.historyDir = filepath.Join(dir, app, "history")
+		}
+	}
+}
+
+// WithHistoryLimit sets the most entries kept in each prompt's history
+func WithHistoryLimit(limit int) Option {
+	return func(ctx *Ctx) {
+		ctx.historyLimit = limit
+	}
+}
+
+// WithHistoryID returns a copy of the context whose prompts share the history
+// saved with the id, instead of keying the history by their labels.
+func (ctx *Ctx) WithHistoryID(id string) *Ctx {
+	newCtx := *ctx
+	newCtx.historyID = id
+	return &newCtx
+}
+
+// WithoutHistory returns a copy of the context whose prompts do not recall or
+// save history, for prompts that ask for sensitive input.
+func (ctx *Ctx) WithoutHistory() *Ctx {
+	newCtx := *ctx
+	newCtx.historyOff = true
+	return &newCtx
+}
+
+// historyPath is the file that the history of the prompt with the label is saved
+// to, or empty if the prompt has no history.
+func (ctx *Ctx) historyPath(label string) string {
+	if ctx.historyDir == "" || ctx.historyOff {
+		return ""
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, strings.TrimSpace(cmp.Or(ctx.historyID, label)))
+	if name = strings.Trim(name, "_"); name == "" {
+		return ""
+	}
+	return filepath.Join(ctx.historyDir, name)
+}
+
+// historyConfig sets up the history of a readline config for the prompt. The
+// history is saved by saveHistory rather than by readline, which saves it after
+// returning the line and so could be closed before it is saved.
+func (ctx *Ctx) historyConfig(label string, c *readline.Config) *readline.Config {
+	c.DisableAutoSaveHistory = true
+	if path := ctx.historyPath(label); path != "" && os.MkdirAll(filepath.Dir(path), 0o700) == nil {
+		c.HistoryFile = path
+		// readline counts the line being edited as part of the history
+		c.HistoryLimit = cmp.Or(ctx.historyLimit, historyLimit) + 1
+		c.HistorySearchFold = true
+	}
+	return c
+}
+
+// saveHistory adds the input to the history of the line reader
+func (lr *lineReader) saveHistory(input string) {
+	if strings.TrimSpace(input) != "" {
+		lr.SaveHistory(input)
+	}
+}
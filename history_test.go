@@ -0,0 +1,72 @@
+package gluey_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+// historyFile points the user's cache directory at a temporary directory and
+// returns the file that the history of the prompt will be saved to.
+func historyFile(t *testing.T, app, name string) string {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		t.Skip("no cache directory:", err)
+	}
+	return filepath.Join(dir, app, "history", name)
+}
+
+func TestHistory(t *testing.T) {
+	path := historyFile(t, "app", "name")
+	term := gluetest.NewTerminal()
+	ctx := term.Ctx(gluey.WithHistory("app"))
+	term.Type("first", gluetest.KeyEnter)
+	if _, err := ctx.Ask("Name?"); err != nil {
+		t.Fatal(err)
+	}
+	term.Type(gluetest.KeyUp, gluetest.KeyEnter)
+	if name, err := ctx.Ask("Name?"); err != nil || name != "first" {
+		t.Errorf("Ask recalled %q, %v", name, err)
+	}
+	if history, err := os.ReadFile(path); err != nil || string(history) != "first\n" {
+		t.Errorf("history = %q, %v", history, err)
+	}
+}
+
+func TestHistoryOnlySavesValidAnswers(t *testing.T) {
+	path := historyFile(t, "app", "age")
+	term := gluetest.NewTerminal()
+	ctx := term.Ctx(gluey.WithHistory("app"))
+	validate := func(input string) error {
+		if input == "bad" {
+			return errors.New("not a good age")
+		}
+		return nil
+	}
+	term.Type("bad", gluetest.KeyEnter, "42", gluetest.KeyEnter)
+	if age, err := ctx.AskValidated("Age?", validate); err != nil || age != "42" {
+		t.Fatalf("AskValidated = %q, %v", age, err)
+	}
+	if history, err := os.ReadFile(path); err != nil || string(history) != "42\n" {
+		t.Errorf("history = %q, %v", history, err)
+	}
+}
+
+func TestWithoutHistory(t *testing.T) {
+	path := historyFile(t, "app", "secret")
+	term := gluetest.NewTerminal()
+	term.Type("hunter2", gluetest.KeyEnter)
+	if _, err := term.Ctx(gluey.WithHistory("app")).WithoutHistory().Ask("Secret?"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("history was saved: %v", err)
+	}
+}
@@ -0,0 +1,347 @@
+package gluey
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+// sequenceWait is how long a reader of named keys will wait for the rest of an
+// escape sequence that was split over reads, before reading what it has.
+const sequenceWait = 50 * time.Millisecond
+
+// sharedInput reads the input of a context in a single goroutine. Prompts read
+// through an inputReader so that they can stop reading, when they are done or
+// cancelled, without losing any input meant for the next prompt. The input is
+// only read when a prompt asks for it, so that nothing is reading the input
+// between prompts, like when an editor is running.
+type sharedInput struct {
+	in       io.Reader
+	start    sync.Once
+	requests chan struct{}
+	chunks   chan []byte
+	mut      sync.Mutex
+	rest     []byte
+	err      error
+}
+
+// inputReader is a single prompt's view of the shared input
+type inputReader struct {
+	input     *sharedInput
+	stop      chan struct{}
+	once      sync.Once
+	ready     chan struct{}
+	namedKeys bool
+}
+
+func newSharedInput(in io.Reader) *sharedInput {
+	return &sharedInput{in: in, requests: make(chan struct{}), chunks: make(chan []byte)}
+}
+
+func (si *sharedInput) pump() {
+	for range si.requests {
+		buf := make([]byte, 1024)
+		n, err := si.in.Read(buf)
+		if n > 0 {
+			si.chunks <- buf[:n]
+		}
+		if err != nil {
+			si.mut.Lock()
+			si.err = err
+			si.mut.Unlock()
+			close(si.chunks)
+			return
+		}
+	}
+}
+
+func (si *sharedInput) reader() *inputReader {
+	si.start.Do(func() { go si.pump() })
+	return &inputReader{input: si, stop: make(chan struct{})}
+}
+
+// pacedReader creates a reader for readline that reads a key at a time, and
+// only once the previous key has been handled.
+func (si *sharedInput) pacedReader() *inputReader {
+	ir := si.reader()
+	ir.ready = make(chan struct{}, 1)
+	ir.ready <- struct{}{}
+	return ir
+}
+
+// handled marks the last key read as handled so a paced reader can continue
+func (ir *inputReader) handled() {
+	select {
+	case ir.ready <- struct{}{}:
+	default:
+	}
+}
+
+// idle waits until the last key read by a paced reader has been handled
+func (ir *inputReader) idle() {
+	<-ir.ready
+	ir.ready <- struct{}{}
+}
+
+// Read will read the next available input, or return io.EOF once the reader is
+// closed.
+func (ir *inputReader) Read(p []byte) (int, error) {
+	if ir.ready != nil {
+		return ir.readKey(p)
+	}
+	chunk, err := ir.chunk()
+	if err != nil {
+		return 0, err
+	}
+	if ir.namedKeys {
+		chunk = ir.translate(chunk)
+	}
+	n := copy(p, chunk)
+	if n < len(chunk) {
+		ir.unread(chunk[n:])
+	}
+	return n, nil
+}
+
+// readKey reads a single key for readline once the previous key has been
+// handled. Only keys that readline passes on as a single key are read, as
+// readline does not call the listener for the rest, so that every key read is
+// handled before the next is read.
+func (ir *inputReader) readKey(p []byte) (n int, err error) {
+	select {
+	case <-ir.ready:
+	case <-ir.stop:
+		return 0, io.EOF
+	}
+	defer func() {
+		if n == 0 {
+			// no key was read so there is none to be handled
+			ir.handled()
+		}
+	}()
+	for {
+		select {
+		case <-ir.stop:
+			return 0, io.EOF
+		default:
+		}
+		chunk, err := ir.chunk()
+		if err != nil {
+			return 0, err
+		}
+		key, rest := ir.nextKey(chunk)
+		select {
+		case <-ir.stop:
+			ir.unread(chunk)
+			return 0, io.EOF
+		default:
+		}
+		ir.unread(rest)
+		if ir.namedKeys {
+			key = translateKeys(key)
+		}
+		if key = readlineKey(key); len(key) > 0 {
+			n = copy(p, key)
+			return n, nil
+		}
+	}
+}
+
+// chunk takes the input that was put back, or reads the next chunk of input
+func (ir *inputReader) chunk() ([]byte, error) {
+	si := ir.input
+	si.mut.Lock()
+	if len(si.rest) > 0 {
+		rest := si.rest
+		si.rest = nil
+		si.mut.Unlock()
+		return rest, nil
+	}
+	si.mut.Unlock()
+
+	// a chunk may be waiting from a reader that stopped before receiving it,
+	// otherwise ask the pump for more.
+	var chunk []byte
+	var ok bool
+	select {
+	case chunk, ok = <-si.chunks:
+	case si.requests <- struct{}{}:
+		select {
+		case chunk, ok = <-si.chunks:
+		case <-ir.stop:
+			return nil, io.EOF
+		}
+	case <-ir.stop:
+		return nil, io.EOF
+	}
+	if !ok {
+		si.mut.Lock()
+		defer si.mut.Unlock()
+		return nil, si.err
+	}
+	return chunk, nil
+}
+
+// more waits a moment for more input, to complete an escape sequence that was
+// split over reads. Nothing is returned if no input came.
+func (ir *inputReader) more() []byte {
+	si := ir.input
+	wait := time.After(sequenceWait)
+	select {
+	case more := <-si.chunks:
+		return more
+	case si.requests <- struct{}{}:
+		select {
+		case more := <-si.chunks:
+			return more
+		case <-wait:
+		case <-ir.stop:
+		}
+	case <-wait:
+	case <-ir.stop:
+	}
+	return nil
+}
+
+// nextKey splits the first key from the chunk, reading on while the chunk is
+// only part of an escape sequence so that a sequence split over reads is read
+// whole. An Esc that is not followed by the rest of a sequence is a key of its
+// own.
+func (ir *inputReader) nextKey(chunk []byte) (key, rest []byte) {
+	for {
+		n := keyLength(chunk)
+		if n < len(chunk) || !partialSequence(chunk) {
+			return chunk[:n], chunk[n:]
+		}
+		more := ir.more()
+		if len(more) == 0 || more[0] == readline.CharEsc || (len(chunk) == 1 && more[0] != '[' && more[0] != 'O') {
+			return chunk, more
+		}
+		chunk = append(chunk, more...)
+	}
+}
+
+// keyLength is the length of the first key of the input, which is an escape
+// sequence, an Esc followed by a key for Alt, or a single character.
+func keyLength(input []byte) int {
+	if input[0] != readline.CharEsc {
+		_, size := utf8.DecodeRune(input)
+		return size
+	} else if len(input) == 1 || input[1] == readline.CharEsc {
+		return 1
+	}
+	switch input[1] {
+	case '[':
+		for i, c := range input[2:] {
+			if c < 0x20 || c > 0x3f {
+				return i + 3
+			}
+		}
+		return len(input)
+	case 'O':
+		return min(3, len(input))
+	}
+	_, size := utf8.DecodeRune(input[1:])
+	return 1 + size
+}
+
+// readlineKey is the key as it can be read by readline, or nil if readline
+// would not pass it on. readline waits for the next key after a lone Esc or an
+// incomplete sequence, drops sequences that it does not know, splits SS3
+// sequences other than Home and End, and stops reading at a NUL.
+func readlineKey(key []byte) []byte {
+	switch {
+	case len(key) == 0 || key[0] == 0:
+		return nil
+	case key[0] != readline.CharEsc:
+		return key
+	case len(key) == 1 || partialSequence(key):
+		return nil
+	case key[1] == 'O' && bytes.IndexByte([]byte("ABCDHF"), key[2]) >= 0:
+		return []byte{readline.CharEsc, '[', key[2]}
+	case key[1] == 'O':
+		return nil
+	case key[1] != '[':
+		return key
+	}
+	params, final := key[2:len(key)-1], key[len(key)-1]
+	numeric := !bytes.ContainsFunc(params, func(r rune) bool { return r != ';' && !unicode.IsDigit(r) })
+	if (numeric && bytes.IndexByte([]byte("ABCDHF"), final) >= 0) || (final == '~' && string(params) == "3") {
+		return key
+	}
+	return nil
+}
+
+// translate translates the keys of the chunk, reading on while it ends in part
+// of an escape sequence so that a sequence split over reads is translated whole.
+// An Esc that is not followed by the rest of a sequence is a key of its own, and
+// the input after it is translated separately.
+func (ir *inputReader) translate(chunk []byte) []byte {
+	for {
+		start := bytes.LastIndexByte(chunk, readline.CharEsc)
+		if start < 0 || !partialSequence(chunk[start:]) {
+			return translateKeys(chunk)
+		}
+		more := ir.more()
+		lone := start == len(chunk)-1
+		if len(more) == 0 || more[0] == readline.CharEsc || (lone && more[0] != '[' && more[0] != 'O') {
+			if lone {
+				chunk = append(translateKeys(chunk[:start]), string(KeyEsc)...)
+			} else {
+				chunk = translateKeys(chunk)
+			}
+			if len(more) == 0 {
+				return chunk
+			}
+			return append(chunk, ir.translate(more)...)
+		}
+		chunk = append(chunk, more...)
+	}
+}
+
+// partialSequence is true if b is an escape sequence that is not complete, which
+// is a lone Esc, or a CSI or SS3 sequence without its final byte.
+func partialSequence(b []byte) bool {
+	if len(b) == 0 || b[0] != readline.CharEsc {
+		return false
+	} else if len(b) == 1 {
+		return true
+	} else if b[1] == 'O' {
+		return len(b) == 2
+	} else if b[1] != '[' {
+		return false
+	}
+	for _, c := range b[2:] {
+		// parameter and intermediate bytes, anything else ends the sequence
+		if c < 0x20 || c > 0x3f {
+			return false
+		}
+	}
+	return true
+}
+
+// ended is true once the input has returned an error, like io.EOF
+func (si *sharedInput) ended() bool {
+	si.mut.Lock()
+	defer si.mut.Unlock()
+	return si.err != nil
+}
+
+// unread puts input back so that it is the next input read
+func (ir *inputReader) unread(b []byte) {
+	si := ir.input
+	si.mut.Lock()
+	defer si.mut.Unlock()
+	si.rest = append(append([]byte{}, b...), si.rest...)
+}
+
+// Close stops the reader without affecting the shared input
+func (ir *inputReader) Close() error {
+	ir.once.Do(func() { close(ir.stop) })
+	return nil
+}
@@ -0,0 +1,328 @@
+package gluey
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/chzyer/readline"
+	"github.com/tanema/gluey/term"
+)
+
+// KeyAction is an action that a key can be bound to in a select prompt
+type KeyAction int
+
+// Keymap binds keys to actions in select prompts. Keys are the runes read from
+// the terminal, special keys are available as the Key constants and control
+// keys from CtrlKey.
+type Keymap map[rune]KeyAction
+
+// Actions that keys can be bound to
+const (
+	ActionUp KeyAction = iota + 1
+	ActionDown
+	ActionHome
+	ActionEnd
+	ActionPageUp
+	ActionPageDown
+	ActionExpand
+	ActionCollapse
+	ActionChoose
+	ActionFilter
+	ActionExit
+	ActionAll
+	ActionNone
+	ActionInvert
+)
+
+// Special keys as they are read in a select prompt. Some terminals send the
+// same rune for a key and a control key, like ↑ and Ctrl-P, so they can not be
+// bound separately. Delete is read as Ctrl-D everywhere but AnyKey.
+const (
+	KeyUp       rune = readline.CharPrev
+	KeyDown     rune = readline.CharNext
+	KeyLeft     rune = readline.CharBackward
+	KeyRight    rune = readline.CharForward
+	KeyHome     rune = readline.CharLineStart
+	KeyEnd      rune = readline.CharLineEnd
+	KeyEnter    rune = readline.CharEnter
+	KeyEsc      rune = '\uE002'
+	KeyPageUp   rune = '\uE000'
+	KeyPageDown rune = '\uE001'
+	KeyInsert   rune = '\uE003'
+	KeyDelete   rune = '\uE004'
+	KeyF1       rune = '\uE005'
+	KeyF2       rune = '\uE006'
+	KeyF3       rune = '\uE007'
+	KeyF4       rune = '\uE008'
+	KeyF5       rune = '\uE009'
+	KeyF6       rune = '\uE00A'
+	KeyF7       rune = '\uE00B'
+	KeyF8       rune = '\uE00C'
+	KeyF9       rune = '\uE00D'
+	KeyF10      rune = '\uE00E'
+	KeyF11      rune = '\uE00F'
+	KeyF12      rune = '\uE010'
+)
+
+// keyNames are the names of keys shown in help, in the order that they are
+// preferred.
+var keyNames = []struct {
+	key  rune
+	name string
+}{
+	{KeyUp, "↑"},
+	{KeyDown, "↓"},
+	{KeyRight, "→"},
+	{KeyLeft, "←"},
+	{KeyEnter, term.ReturnLabel},
+	{' ', "Space"},
+	{KeyEsc, "Esc"},
+	{KeyHome, "Home"},
+	{KeyEnd, "End"},
+	{KeyPageUp, "PgUp"},
+	{KeyPageDown, "PgDn"},
+	{KeyInsert, "Ins"},
+	{KeyDelete, "Del"},
+	{KeyF1, "F1"},
+	{KeyF2, "F2"},
+	{KeyF3, "F3"},
+	{KeyF4, "F4"},
+	{KeyF5, "F5"},
+	{KeyF6, "F6"},
+	{KeyF7, "F7"},
+	{KeyF8, "F8"},
+	{KeyF9, "F9"},
+	{KeyF10, "F10"},
+	{KeyF11, "F11"},
+	{KeyF12, "F12"},
+}
+
+// keySequences translates the escape sequences of keys that readline does not
+// handle, or only handles in some terminals.
+var keySequences = []struct{ seq, key []byte }{
+	{[]byte("\x1b[1~"), []byte("\x1b[H")},
+	{[]byte("\x1b[7~"), []byte("\x1b[H")},
+	{[]byte("\x1b[4~"), []byte("\x1b[F")},
+	{[]byte("\x1b[8~"), []byte("\x1b[F")},
+	{[]byte("\x1b[5~"), []byte(string(KeyPageUp))},
+	{[]byte("\x1b[6~"), []byte(string(KeyPageDown))},
+	{[]byte("\x1b[2~"), []byte(string(KeyInsert))},
+	{[]byte("\x1bOP"), []byte(string(KeyF1))},
+	{[]byte("\x1bOQ"), []byte(string(KeyF2))},
+	{[]byte("\x1bOR"), []byte(string(KeyF3))},
+	{[]byte("\x1bOS"), []byte(string(KeyF4))},
+	{[]byte("\x1b[11~"), []byte(string(KeyF1))},
+	{[]byte("\x1b[12~"), []byte(string(KeyF2))},
+	{[]byte("\x1b[13~"), []byte(string(KeyF3))},
+	{[]byte("\x1b[14~"), []byte(string(KeyF4))},
+	{[]byte("\x1b[15~"), []byte(string(KeyF5))},
+	{[]byte("\x1b[17~"), []byte(string(KeyF6))},
+	{[]byte("\x1b[18~"), []byte(string(KeyF7))},
+	{[]byte("\x1b[19~"), []byte(string(KeyF8))},
+	{[]byte("\x1b[20~"), []byte(string(KeyF9))},
+	{[]byte("\x1b[21~"), []byte(string(KeyF10))},
+	{[]byte("\x1b[23~"), []byte(string(KeyF11))},
+	{[]byte("\x1b[24~"), []byte(string(KeyF12))},
+}
+
+// WithKeymap sets the keys used to control a select prompt. The help text is
+// generated from the keymap.
+func WithKeymap(keymap Keymap) SelectOption {
+	return func(s *Selector) {
+		s.keymap = keymap
+	}
+}
+
+// CtrlKey returns the rune that is read when r is pressed with Ctrl
+func CtrlKey(r rune) rune {
+	return unicode.ToUpper(r) & 0x1f
+}
+
+// DefaultKeymap uses the arrow keys along with j, k, h and l to move, f or / to
+// filter and enter or space to choose.
+func DefaultKeymap() Keymap {
+	return Keymap{
+		KeyUp:               ActionUp,
+		'k':                 ActionUp,
+		KeyDown:             ActionDown,
+		'j':                 ActionDown,
+		KeyHome:             ActionHome,
+		KeyEnd:              ActionEnd,
+		KeyPageUp:           ActionPageUp,
+		KeyPageDown:         ActionPageDown,
+		KeyRight:            ActionExpand,
+		'l':                 ActionExpand,
+		KeyLeft:             ActionCollapse,
+		'h':                 ActionCollapse,
+		KeyEnter:            ActionChoose,
+		' ':                 ActionChoose,
+		'f':                 ActionFilter,
+		'/':                 ActionFilter,
+		KeyEsc:              ActionExit,
+		readline.CharDelete: ActionExit,
+		'a':                 ActionAll,
+		'n':                 ActionNone,
+		'i':                 ActionInvert,
+	}
+}
+
+// VimKeymap moves with h, j, k and l, g and G for the first and last items and
+// Ctrl-U and Ctrl-D to page. / filters and Esc exits the filter.
+func VimKeymap() Keymap {
+	return Keymap{
+		KeyUp:        ActionUp,
+		'k':          ActionUp,
+		KeyDown:      ActionDown,
+		'j':          ActionDown,
+		'g':          ActionHome,
+		'G':          ActionEnd,
+		KeyHome:      ActionHome,
+		KeyEnd:       ActionEnd,
+		CtrlKey('u'): ActionPageUp,
+		CtrlKey('d'): ActionPageDown,
+		KeyPageUp:    ActionPageUp,
+		KeyPageDown:  ActionPageDown,
+		KeyRight:     ActionExpand,
+		'l':          ActionExpand,
+		KeyLeft:      ActionCollapse,
+		'h':          ActionCollapse,
+		KeyEnter:     ActionChoose,
+		' ':          ActionChoose,
+		'/':          ActionFilter,
+		KeyEsc:       ActionExit,
+		'a':          ActionAll,
+		'n':          ActionNone,
+		'i':          ActionInvert,
+	}
+}
+
+// EmacsKeymap moves with Ctrl-N, Ctrl-P, Ctrl-F and Ctrl-B, Ctrl-A and Ctrl-E
+// for the first and last items and Ctrl-V to page. Ctrl-G exits the filter.
+func EmacsKeymap() Keymap {
+	return Keymap{
+		CtrlKey('p'): ActionUp,
+		CtrlKey('n'): ActionDown,
+		CtrlKey('a'): ActionHome,
+		CtrlKey('e'): ActionEnd,
+		KeyPageUp:    ActionPageUp,
+		CtrlKey('v'): ActionPageDown,
+		KeyPageDown:  ActionPageDown,
+		CtrlKey('f'): ActionExpand,
+		CtrlKey('b'): ActionCollapse,
+		KeyEnter:     ActionChoose,
+		' ':          ActionChoose,
+		'/':          ActionFilter,
+		CtrlKey('g'): ActionExit,
+		KeyEsc:       ActionExit,
+		'a':          ActionAll,
+		'n':          ActionNone,
+		'i':          ActionInvert,
+	}
+}
+
+// keys lists the keys bound to the action, with the preferred key first
+func (keymap Keymap) keys(action KeyAction) []rune {
+	keys := []rune{}
+	for key, bound := range keymap {
+		if bound == action {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b rune) int {
+		if rankA, rankB := keyRank(a), keyRank(b); rankA != rankB {
+			return rankA - rankB
+		}
+		return int(a - b)
+	})
+	return keys
+}
+
+// keyRank orders named keys first, then letters and then anything else
+func keyRank(key rune) int {
+	for i, named := range keyNames {
+		if named.key == key {
+			return i
+		}
+	}
+	if unicode.IsLetter(key) {
+		return len(keyNames)
+	}
+	return len(keyNames) + 1
+}
+
+// keyName is how a key is shown in help
+func keyName(key rune) string {
+	for _, named := range keyNames {
+		if named.key == key {
+			return named.name
+		}
+	}
+	if key < ' ' {
+		return fmt.Sprintf("Ctrl-%c", key+'@')
+	}
+	return fmt.Sprintf("'%c'", key)
+}
+
+// help names the preferred key of each action, skipping unbound actions
+func (keymap Keymap) help(actions ...KeyAction) []string {
+	names := []string{}
+	for _, action := range actions {
+		if keys := keymap.keys(action); len(keys) > 0 {
+			names = append(names, keyName(keys[0]))
+		}
+	}
+	return names
+}
+
+// helpText describes how to use the select with the keymap
+func (keymap Keymap) helpText(multiple bool) string {
+	verb := "Choose"
+	if multiple {
+		verb = "Toggle"
+	}
+	parts := []string{verb + " with " + strings.Join(keymap.help(ActionUp, ActionDown, ActionChoose), " ")}
+	if filter := keymap.help(ActionFilter); len(filter) > 0 {
+		parts = append(parts, "filter with "+filter[0])
+	}
+	if multiple {
+		names, keys := []string{}, []string{}
+		for _, bulk := range []struct {
+			name   string
+			action KeyAction
+		}{{"all", ActionAll}, {"none", ActionNone}, {"invert", ActionInvert}} {
+			if key := keymap.help(bulk.action); len(key) > 0 {
+				names, keys = append(names, bulk.name), append(keys, key[0])
+			}
+		}
+		if len(keys) > 0 {
+			parts = append(parts, strings.Join(names, "/")+" with "+strings.Join(keys, "/"))
+		}
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// exitHelp names all of the keys that exit the filter or select modes
+func (keymap Keymap) exitHelp() string {
+	names := []string{}
+	for _, key := range keymap.keys(ActionExit) {
+		names = append(names, keyName(key))
+	}
+	return strings.Join(names, ", ")
+}
+
+// translateKeys rewrites the escape sequences of keys that readline does not
+// handle into runes that it will pass on to the select. readline never passes
+// on Esc, as it waits for the sequence that it could start, so an Esc that is
+// read on its own, which is a key press, is translated as well.
+func translateKeys(chunk []byte) []byte {
+	if bytes.Equal(chunk, []byte{readline.CharEsc}) {
+		return []byte(string(KeyEsc))
+	}
+	for _, sequence := range keySequences {
+		chunk = bytes.ReplaceAll(chunk, sequence.seq, sequence.key)
+	}
+	return chunk
+}
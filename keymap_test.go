@@ -0,0 +1,74 @@
+package gluey_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestSelectKeymaps(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	cases := []struct {
+		name   string
+		keymap gluey.Keymap
+		keys   []string
+		want   string
+	}{
+		{"default end", gluey.DefaultKeymap(), []string{"\x1b[F", gluetest.KeyEnter}, "d"},
+		{"default page down", gluey.DefaultKeymap(), []string{"\x1b[6~", gluetest.KeyEnter}, "d"},
+		{"default jk", gluey.DefaultKeymap(), []string{"j", "j", "k", " "}, "b"},
+		{"vim", gluey.VimKeymap(), []string{"G", "k", gluetest.KeyEnter}, "c"},
+		{"vim home", gluey.VimKeymap(), []string{"G", "g", gluetest.KeyEnter}, "a"},
+		{"emacs", gluey.EmacsKeymap(), []string{"\x0e", "\x0e", "\x10", gluetest.KeyEnter}, "b"},
+		{"emacs end", gluey.EmacsKeymap(), []string{"\x05", gluetest.KeyEnter}, "d"},
+		{"custom", gluey.Keymap{'s': gluey.ActionDown, 'x': gluey.ActionChoose}, []string{"s", "s", "x"}, "c"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := gluetest.NewTerminal()
+			term.Type(c.keys...)
+			if _, item, err := term.Ctx().Select("Pick", items, gluey.WithKeymap(c.keymap)); err != nil || item != c.want {
+				t.Errorf("Select = %q, %v, want %q", item, err, c.want)
+			}
+		})
+	}
+}
+
+func TestSelectKeymapHelp(t *testing.T) {
+	term := gluetest.NewTerminal()
+	done := make(chan struct{})
+	go func() {
+		term.Ctx().Select("Pick", []string{"a"}, gluey.WithKeymap(gluey.VimKeymap()))
+		close(done)
+	}()
+	waitFor(t, term, "Pick")
+	if screen := term.Screen(); !strings.Contains(screen, "filter with '/'") {
+		t.Errorf("help does not follow the keymap:\n%s", screen)
+	}
+	term.Type(gluetest.KeyEnter)
+	<-done
+}
+
+func TestSelectSplitSequences(t *testing.T) {
+	cases := []struct {
+		name string
+		keys []string
+		want string
+	}{
+		{"arrow", []string{"\x1b", "[B", gluetest.KeyEnter}, "b"},
+		{"page down", []string{"\x1b[", "6", "~", gluetest.KeyEnter}, "d"},
+		{"end", []string{"\x1b", "[4~", gluetest.KeyEnter}, "d"},
+		{"esc then key", []string{"f", "c", gluetest.KeyEsc, "j", gluetest.KeyEnter}, "b"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := gluetest.NewTerminal()
+			term.Type(c.keys...)
+			if _, item, err := term.Ctx().Select("Pick", []string{"a", "b", "c", "d"}); err != nil || item != c.want {
+				t.Errorf("Select = %q, %v, want %q", item, err, c.want)
+			}
+		})
+	}
+}
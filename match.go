@@ -0,0 +1,110 @@
+package gluey
+
+import "unicode"
+
+// Matcher decides if a select item label matches the filter term. It returns a
+// score used to order the results, higher scores first, and the rune indexes of
+// the label that matched so that they can be highlighted.
+type Matcher func(term, label string) (score int, positions []int, ok bool)
+
+const (
+	scoreMatch       = 16
+	scoreBoundary    = 8
+	scoreConsecutive = 4
+	scoreGapStart    = -3
+	scoreGapExtend   = -1
+)
+
+// WithMatcher sets the matcher used to filter select items, FuzzyMatcher is used
+// by default.
+func WithMatcher(matcher Matcher) Option {
+	return func(ctx *Ctx) {
+		ctx.matcher = matcher
+	}
+}
+
+// FuzzyMatcher matches labels that contain the characters of the term in order,
+// so that "vsc" will match "VSCode". Matches at the start of words and runs of
+// consecutive characters are scored higher, and gaps between them lower.
+func FuzzyMatcher(term, label string) (int, []int, bool) {
+	needle := lowerRunes(term)
+	haystack := []rune(label)
+	if len(needle) == 0 {
+		return 0, nil, true
+	}
+	best, bestPositions, found := 0, []int(nil), false
+	for start := range haystack {
+		if unicode.ToLower(haystack[start]) != needle[0] {
+			continue
+		}
+		score, positions, ok := fuzzyMatchFrom(needle, haystack, start)
+		if ok && (!found || score > best) {
+			best, bestPositions, found = score, positions, true
+		}
+	}
+	return best, bestPositions, found
+}
+
+// fuzzyMatchFrom greedily matches the needle in the haystack from start
+func fuzzyMatchFrom(needle, haystack []rune, start int) (int, []int, bool) {
+	score := 0
+	positions := make([]int, 0, len(needle))
+	for i := start; i < len(haystack) && len(positions) < len(needle); i++ {
+		if unicode.ToLower(haystack[i]) != needle[len(positions)] {
+			continue
+		}
+		score += scoreMatch
+		if isWordBoundary(haystack, i) {
+			score += scoreBoundary
+		}
+		if len(positions) > 0 {
+			if gap := i - positions[len(positions)-1] - 1; gap == 0 {
+				score += scoreConsecutive
+			} else {
+				score += scoreGapStart + scoreGapExtend*(gap-1)
+			}
+		}
+		positions = append(positions, i)
+	}
+	return score, positions, len(positions) == len(needle)
+}
+
+// isWordBoundary is true when the rune at i starts a word, either after a
+// separator or as an upper case letter in camel case.
+func isWordBoundary(runes []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := runes[i-1], runes[i]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// SubstringMatcher matches labels that contain the term, ignoring case. Matches
+// closer to the start of the label are scored higher.
+func SubstringMatcher(term, label string) (int, []int, bool) {
+	lowerLabel := lowerRunes(label)
+	needle := lowerRunes(term)
+	for start := 0; start+len(needle) <= len(lowerLabel); start++ {
+		if string(lowerLabel[start:start+len(needle)]) != string(needle) {
+			continue
+		}
+		positions := make([]int, len(needle))
+		for i := range positions {
+			positions[i] = start + i
+		}
+		return -start, positions, true
+	}
+	return 0, nil, false
+}
+
+// lowerRunes lower cases each rune so that the indexes match the original string
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
@@ -0,0 +1,58 @@
+package gluey_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestFuzzyMatcher(t *testing.T) {
+	_, positions, ok := gluey.FuzzyMatcher("vsc", "VSCode")
+	if !ok || !slices.Equal(positions, []int{0, 1, 2}) {
+		t.Errorf("FuzzyMatcher = %v, %v", positions, ok)
+	}
+	if _, _, ok := gluey.FuzzyMatcher("xyz", "VSCode"); ok {
+		t.Error("FuzzyMatcher matched characters that are not in the label")
+	}
+	if _, _, ok := gluey.FuzzyMatcher("edoc", "VSCode"); ok {
+		t.Error("FuzzyMatcher matched characters out of order")
+	}
+	boundary, _, _ := gluey.FuzzyMatcher("sc", "Sub Code")
+	gap, _, _ := gluey.FuzzyMatcher("sc", "Subscript")
+	if boundary <= gap {
+		t.Errorf("word boundaries scored %v, not above %v", boundary, gap)
+	}
+}
+
+func TestSubstringMatcher(t *testing.T) {
+	score, positions, ok := gluey.SubstringMatcher("od", "VSCode")
+	if !ok || !slices.Equal(positions, []int{3, 4}) {
+		t.Errorf("SubstringMatcher = %v, %v", positions, ok)
+	}
+	if earlier, _, _ := gluey.SubstringMatcher("od", "Ode"); earlier <= score {
+		t.Errorf("earlier match scored %v, not above %v", earlier, score)
+	}
+	if _, _, ok := gluey.SubstringMatcher("vsc", "VSCode "); !ok {
+		t.Error("SubstringMatcher did not match a prefix")
+	}
+}
+
+func TestSelectFilter(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("f", "v", "s", "c", gluetest.KeyEnter, gluetest.KeyEnter)
+	_, item, err := term.Ctx().Select("Editor?", []string{"Vim", "Emacs", "Visual Studio Code", "VSCode"})
+	if err != nil || item != "VSCode" {
+		t.Errorf("Select = %q, %v", item, err)
+	}
+}
+
+func TestSelectFilterWithMatcher(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("f", "o", "d", "e", gluetest.KeyEnter, gluetest.KeyEnter)
+	ctx := term.Ctx(gluey.WithMatcher(gluey.SubstringMatcher))
+	if _, item, err := ctx.Select("Editor?", []string{"Visual Studio Code", "VSCode"}); err != nil || item != "VSCode" {
+		t.Errorf("Select = %q, %v", item, err)
+	}
+}
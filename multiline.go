@@ -0,0 +1,289 @@
+package gluey
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"unicode"
+
+	"github.com/chzyer/readline"
+	"github.com/tanema/gluey/term"
+)
+
+const multilineTemplate = `{{.Prefix}}
+{{- if .Cancelled -}}
+	{{ iconBad }} {{ .Label }} {{ "(cancelled)" | faint }}
+{{- else -}}
+{{iconQ}} {{.Label}}
+{{- if not .Done }} {{ .HelpText | yellow }}{{ end }}
+{{- range .Rows }}
+{{ $.Prefix }}
+	{{- if $.Done -}}
+		{{ "┃" | faint }} {{ .Before }}
+	{{- else -}}
+		{{ "┃" | blue }} {{ .Before }}{{ if .Cursor }}{{ .At | underline }}{{ end }}{{ .After }}
+	{{- end }}
+{{- end }}
+{{- end }}`
+
+type multilineRow struct {
+	Before string
+	At     string
+	After  string
+	Cursor bool
+}
+
+// textArea is an inline multi-line editor
+type textArea struct {
+	ctx       *Ctx
+	label     string
+	lines     [][]rune
+	row       int
+	col       int
+	done      bool
+	cancelled bool
+}
+
+// AskMultiline will prompt the user for multiple lines of input in an inline
+// editing area. Enter adds a new line and Ctrl-D submits the input.
+func (ctx *Ctx) AskMultiline(label string) (string, error) {
+	if ctx.nonInteractive {
+		ctx.Println(Fmt(`{{iconQ}} {{.}}`, label))
+		return ctx.answer(label, "")
+	}
+	return (&textArea{ctx: ctx, label: label, lines: [][]rune{{}}}).run()
+}
+
+func AskMultiline(label string) (string, error) {
+	return New().AskMultiline(label)
+}
+
+// AskEditor will open the user's $VISUAL or $EDITOR on a temporary file seeded
+// with the template and return the edited content. Lines starting with # are
+// treated as comments and removed, so they can be used to give instructions in
+// the template.
+func (ctx *Ctx) AskEditor(label, template string) (string, error) {
+	ctx.Println(Fmt(`{{iconQ}} {{.}}`, label))
+	if ctx.nonInteractive {
+		return ctx.answer(label, stripComments(template))
+	}
+
+	file, err := os.CreateTemp("", "gluey-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(template)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := ctx.runEditor(file.Name()); err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return stripComments(string(content)), nil
+}
+
+func AskEditor(label, template string) (string, error) {
+	return New().AskEditor(label, template)
+}
+
+func (ta *textArea) run() (string, error) {
+	rl, err := ta.ctx.newLineReader(&readline.Config{
+		HistoryLimit:   -1,
+		UniqueEditLine: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := rl.readKeys(ta); err != nil {
+		return "", err
+	}
+	return ta.String(), nil
+}
+
+func (ta *textArea) finished() bool {
+	return ta.done
+}
+
+func (ta *textArea) cancel(err error) error {
+	if err != nil {
+		ta.cancelled = true
+	}
+	return err
+}
+
+func (ta *textArea) listen(key rune) {
+	line := ta.lines[ta.row]
+	switch key {
+	case readline.CharDelete:
+		ta.done = true
+	case readline.CharInterrupt:
+		ta.cancelled = true
+	case readline.CharEnter:
+		rest := append([]rune{}, line[ta.col:]...)
+		ta.lines[ta.row] = line[:ta.col]
+		ta.lines = append(ta.lines[:ta.row+1], append([][]rune{rest}, ta.lines[ta.row+1:]...)...)
+		ta.row++
+		ta.col = 0
+	case readline.CharBackspace, readline.CharCtrlH:
+		if ta.col > 0 {
+			ta.lines[ta.row] = append(line[:ta.col-1], line[ta.col:]...)
+			ta.col--
+		} else if ta.row > 0 {
+			ta.col = len(ta.lines[ta.row-1])
+			ta.lines[ta.row-1] = append(ta.lines[ta.row-1], line...)
+			ta.lines = append(ta.lines[:ta.row], ta.lines[ta.row+1:]...)
+			ta.row--
+		}
+	case readline.CharBackward:
+		if ta.col > 0 {
+			ta.col--
+		} else if ta.row > 0 {
+			ta.row--
+			ta.col = len(ta.lines[ta.row])
+		}
+	case readline.CharForward:
+		if ta.col < len(line) {
+			ta.col++
+		} else if ta.row < len(ta.lines)-1 {
+			ta.row++
+			ta.col = 0
+		}
+	case readline.CharPrev:
+		if ta.row > 0 {
+			ta.row--
+			ta.col = min(ta.col, len(ta.lines[ta.row]))
+		}
+	case readline.CharNext:
+		if ta.row < len(ta.lines)-1 {
+			ta.row++
+			ta.col = min(ta.col, len(ta.lines[ta.row]))
+		}
+	case readline.CharLineStart:
+		ta.col = 0
+	case readline.CharLineEnd:
+		ta.col = len(line)
+	default:
+		if unicode.IsPrint(key) {
+			ta.lines[ta.row] = append(line[:ta.col], append([]rune{key}, line[ta.col:]...)...)
+			ta.col++
+		}
+	}
+}
+
+func (ta *textArea) render(sb *term.ScreenBuf) {
+	rows := make([]multilineRow, len(ta.lines))
+	for i, line := range ta.lines {
+		rows[i] = multilineRow{Before: string(line)}
+		if i == ta.row && !ta.done {
+			rows[i] = multilineRow{Before: string(line[:ta.col]), At: " ", Cursor: true}
+			if ta.col < len(line) {
+				rows[i].At = string(line[ta.col])
+				rows[i].After = string(line[ta.col+1:])
+			}
+		}
+	}
+	sb.WriteTmpl(multilineTemplate, struct {
+		Prefix, Label, HelpText string
+		Rows                    []multilineRow
+		Done, Cancelled         bool
+	}{
+		Prefix:    ta.ctx.Prefix(),
+		Label:     ta.label,
+		HelpText:  "(Ctrl-D to submit)",
+		Rows:      rows,
+		Done:      ta.done,
+		Cancelled: ta.cancelled,
+	})
+}
+
+func (ta *textArea) String() string {
+	lines := make([]string, len(ta.lines))
+	for i, line := range ta.lines {
+		lines[i] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runEditor opens the editor on the path, attached to the context's streams. If
+// the input is not a file it is piped to the editor, so keys typed ahead of the
+// editor exiting may be lost.
+func (ctx *Ctx) runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" && runtime.GOOS == "windows" {
+		editor = "notepad"
+	} else if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return errors.New("no editor set in $VISUAL or $EDITOR")
+	}
+
+	cmd := exec.CommandContext(ctx.Context(), args[0], append(args[1:], path)...)
+	cmd.Stdout = ctx.Writer()
+	cmd.Stderr = ctx.errWriter()
+	if f, ok := ctx.inputSource().(*os.File); ok {
+		cmd.Stdin = f
+		return cmd.Run()
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	input := ctx.keys().reader()
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		defer stdin.Close()
+		pipeInput(stdin, input)
+	}()
+	err = cmd.Wait()
+	input.Close()
+	<-copied
+	return err
+}
+
+// pipeInput copies the input to the editor, any input that the editor did not
+// take before exiting is put back for the next prompt.
+func pipeInput(w io.Writer, input *inputReader) {
+	buf := make([]byte, 1024)
+	for {
+		n, err := input.Read(buf)
+		if err != nil {
+			return
+		}
+		if written, werr := w.Write(buf[:n]); werr != nil {
+			input.unread(buf[written:n])
+			return
+		}
+	}
+}
+
+// stripComments removes lines starting with # and surrounding whitespace
+func stripComments(content string) string {
+	lines := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
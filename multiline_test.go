@@ -0,0 +1,52 @@
+package gluey_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestAskMultiline(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("a", "b", gluetest.KeyEnter, "c", gluetest.KeyUp, gluetest.KeyBackspace, gluetest.KeyCtrlD)
+	if input, err := term.Ctx().AskMultiline("Notes?"); err != nil || input != "b\nc" {
+		t.Errorf("AskMultiline = %q, %v", input, err)
+	}
+}
+
+func TestAskMultilineInterrupted(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("a", gluetest.KeyCtrlC)
+	if _, err := term.Ctx().AskMultiline("Notes?"); !errors.Is(err, gluey.ErrInterrupted) {
+		t.Errorf("AskMultiline = %v, want ErrInterrupted", err)
+	}
+	waitFor(t, term, "Notes? (cancelled)")
+}
+
+func TestAskEditor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the editor is a shell script")
+	}
+	editor := filepath.Join(t.TempDir(), "editor")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\necho edited >> \"$1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+	input, err := gluetest.NewTerminal().Ctx().AskEditor("Message?", "# write a message\ntemplate\n")
+	if err != nil || input != "template\nedited" {
+		t.Errorf("AskEditor = %q, %v", input, err)
+	}
+}
+
+func TestAskEditorNonInteractive(t *testing.T) {
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive())
+	if input, err := ctx.AskEditor("Message?", "# comment\ndefault"); err != nil || input != "default" {
+		t.Errorf("AskEditor = %q, %v", input, err)
+	}
+}
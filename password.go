@@ -0,0 +1,325 @@
+package gluey
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+	"github.com/tanema/gluey/term"
+)
+
+const passwordTemplate = `{{.Prefix}}
+{{- if .Cancelled -}}
+	{{ iconBad }} {{ .Label }} {{ "(cancelled)" | faint }}
+{{- else -}}
+{{ iconQ }} {{ .Label }}{{ if and .Confirming (not .Done) }} {{ "(confirm)" | faint }}{{ end }} {{ .Masked }}
+{{- if not .Done }}{{ " " | underline }}{{ end }}
+{{- with .Meter }}
+{{ $.Prefix }}{{ . }}{{ with $.Feedback }} {{ . | faint }}{{ end }}
+{{- end }}
+{{- with .Err }}
+{{ $.Prefix }}{{ iconBad }} {{ . | red }}
+{{- end }}
+{{- end }}`
+
+// strengthLabels name the scores of a PasswordStrength
+var strengthLabels = []string{"very weak", "weak", "fair", "good", "strong"}
+
+var errPasswordMismatch = errors.New("passwords do not match")
+
+// PasswordStrength is the result of checking a password as it is typed
+type PasswordStrength struct {
+	// Score rates the password from 0 to 4 and is shown as a meter
+	Score int
+	// Feedback describes how the password could be stronger
+	Feedback string
+	// Err rejects the password when it is submitted
+	Err error
+}
+
+// PasswordChecker rates a password. The password must not be kept, as it is
+// zeroed once the checker returns.
+type PasswordChecker func(password []byte) PasswordStrength
+
+// PasswordOption configures a password prompt
+type PasswordOption func(*passwordPrompt)
+
+type passwordPrompt struct {
+	ctx        *Ctx
+	label      string
+	mask       rune
+	confirm    bool
+	checker    PasswordChecker
+	input      []rune
+	first      []rune
+	confirming bool
+	strength   PasswordStrength
+	err        error
+	done       bool
+	cancelErr  error
+}
+
+// AskPassword prompts the user for a password input. Characters are not echoed
+// unless a mask is set with WithMask.
+func (ctx *Ctx) AskPassword(label string, opts ...PasswordOption) (string, error) {
+	var password string
+	err := ctx.AskPasswordFunc(label, func(input []byte) error {
+		password = string(input)
+		return nil
+	}, opts...)
+	return password, err
+}
+
+func AskPassword(label string, opts ...PasswordOption) (string, error) {
+	return New().AskPassword(label, opts...)
+}
+
+// AskPasswordFunc prompts the user for a password and calls fn with it. The
+// password is zeroed once fn returns so that it does not stay in memory, it
+// must not be kept by fn. The error from fn is returned.
+func (ctx *Ctx) AskPasswordFunc(label string, fn func(password []byte) error, opts ...PasswordOption) error {
+	pp := &passwordPrompt{ctx: ctx, label: label}
+	for _, opt := range opts {
+		opt(pp)
+	}
+	password, err := pp.run()
+	defer clear(password)
+	if err != nil {
+		return err
+	}
+	return fn(password)
+}
+
+func AskPasswordFunc(label string, fn func(password []byte) error, opts ...PasswordOption) error {
+	return New().AskPasswordFunc(label, fn, opts...)
+}
+
+// WithConfirmation asks for the password a second time, asking again from the
+// start if the passwords do not match.
+func WithConfirmation() PasswordOption {
+	return func(pp *passwordPrompt) {
+		pp.confirm = true
+	}
+}
+
+// WithMask echoes the mask for each character typed
+func WithMask(mask rune) PasswordOption {
+	return func(pp *passwordPrompt) {
+		pp.mask = mask
+	}
+}
+
+// WithStrength shows the strength of the password from the checker beneath it
+// as it is typed, and rejects passwords that the checker returns an error for.
+func WithStrength(checker PasswordChecker) PasswordOption {
+	return func(pp *passwordPrompt) {
+		pp.checker = checker
+	}
+}
+
+// BasicPasswordChecker rates passwords by their length and the kinds of
+// characters they use, rejecting passwords shorter than minLength.
+func BasicPasswordChecker(minLength int) PasswordChecker {
+	return func(password []byte) PasswordStrength {
+		var lower, upper, digit, other bool
+		length := 0
+		for b := password; len(b) > 0; length++ {
+			r, size := utf8.DecodeRune(b)
+			b = b[size:]
+			switch {
+			case unicode.IsLower(r):
+				lower = true
+			case unicode.IsUpper(r):
+				upper = true
+			case unicode.IsDigit(r):
+				digit = true
+			default:
+				other = true
+			}
+		}
+		kinds := 0
+		for _, used := range []bool{lower, upper, digit, other} {
+			if used {
+				kinds++
+			}
+		}
+		if length < minLength {
+			return PasswordStrength{
+				Feedback: fmt.Sprintf("use at least %v characters", minLength),
+				Err:      fmt.Errorf("must be at least %v characters", minLength),
+			}
+		}
+		strength := PasswordStrength{Score: kinds - 1}
+		if length >= 2*minLength {
+			strength.Score++
+		}
+		strength.Score = min(strength.Score, 4)
+		if kinds < 3 {
+			strength.Feedback = "mix cases, numbers and symbols"
+		}
+		return strength
+	}
+}
+
+func (pp *passwordPrompt) run() ([]byte, error) {
+	if pp.ctx.nonInteractive {
+		pp.ctx.Println(Fmt(`{{iconQ}} {{.}}`, pp.label))
+		answer, err := pp.ctx.answer(pp.label, "")
+		if err != nil {
+			return nil, err
+		} else if err := pp.check([]rune(answer)).Err; err != nil {
+			return nil, &AnswerError{Label: pp.label, Answer: answer, Err: err}
+		}
+		return []byte(answer), nil
+	}
+
+	rl, err := pp.ctx.newLineReader(&readline.Config{
+		HistoryLimit:   -1,
+		UniqueEditLine: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		clear(pp.first)
+		clear(pp.input)
+	}()
+
+	pp.strength = pp.check(nil)
+	rl.hidden = true
+	if err := rl.readKeys(pp); err != nil {
+		return nil, err
+	}
+	return runeBytes(pp.input), nil
+}
+
+func (pp *passwordPrompt) finished() bool {
+	return pp.done || pp.cancelErr != nil
+}
+
+func (pp *passwordPrompt) cancel(err error) error {
+	pp.cancelErr = cmp.Or(pp.cancelErr, err)
+	return pp.cancelErr
+}
+
+func (pp *passwordPrompt) listen(key rune) {
+	pp.err = nil
+	switch key {
+	case readline.CharInterrupt:
+		pp.cancelErr = ErrInterrupted
+	case readline.CharDelete:
+		if len(pp.input) == 0 {
+			pp.cancelErr = ErrAborted
+		}
+	case readline.CharEnter:
+		pp.submit()
+	case readline.CharBackspace, readline.CharCtrlH:
+		if len(pp.input) > 0 {
+			pp.input[len(pp.input)-1] = 0
+			pp.input = pp.input[:len(pp.input)-1]
+		}
+	case readline.CharKill, readline.CharCtrlU:
+		clear(pp.input)
+		pp.input = pp.input[:0]
+	default:
+		if unicode.IsPrint(key) {
+			pp.input = appendRune(pp.input, key)
+		}
+	}
+	if !pp.confirming {
+		pp.strength = pp.check(pp.input)
+	}
+}
+
+// submit accepts the input, asking for it again if it has to be confirmed
+func (pp *passwordPrompt) submit() {
+	switch {
+	case pp.confirming && !slices.Equal(pp.input, pp.first):
+		pp.err = errPasswordMismatch
+		clear(pp.input)
+		clear(pp.first)
+		pp.input, pp.first, pp.confirming = pp.input[:0], nil, false
+	case pp.confirming:
+		pp.done = true
+	case pp.strength.Err != nil:
+		pp.err = pp.strength.Err
+	case pp.confirm:
+		pp.first, pp.input, pp.confirming = pp.input, nil, true
+	default:
+		pp.done = true
+	}
+}
+
+// check rates the input with the checker, if there is one
+func (pp *passwordPrompt) check(input []rune) PasswordStrength {
+	if pp.checker == nil {
+		return PasswordStrength{}
+	}
+	password := runeBytes(input)
+	defer clear(password)
+	return pp.checker(password)
+}
+
+func (pp *passwordPrompt) render(sb *term.ScreenBuf) {
+	data := struct {
+		Prefix, Label, Masked string
+		Meter, Feedback       string
+		Err                   error
+		Confirming            bool
+		Done, Cancelled       bool
+	}{
+		Prefix:     pp.ctx.Prefix(),
+		Label:      pp.label,
+		Err:        pp.err,
+		Confirming: pp.confirming,
+		Done:       pp.done,
+		Cancelled:  pp.cancelErr != nil,
+	}
+	if pp.mask != 0 {
+		data.Masked = strings.Repeat(string(pp.mask), len(pp.input))
+	}
+	if pp.checker != nil && !pp.confirming && !pp.done {
+		data.Meter, data.Feedback = strengthMeter(pp.strength.Score), pp.strength.Feedback
+	}
+	sb.WriteTmpl(passwordTemplate, data)
+}
+
+// strengthMeter renders a score from 0 to 4 as a bar with its label
+func strengthMeter(score int) string {
+	score = max(0, min(score, len(strengthLabels)-1))
+	bar := strings.Repeat("▰", score+1) + strings.Repeat("▱", len(strengthLabels)-score-1)
+	color := "red"
+	if score >= 3 {
+		color = "green"
+	} else if score == 2 {
+		color = "yellow"
+	}
+	return Fmt(`{{ `+color+` .Bar }} {{ .Label }}`, struct{ Bar, Label string }{bar, strengthLabels[score]})
+}
+
+// runeBytes encodes the runes as UTF-8 without making an intermediate string,
+// since strings can not be wiped.
+func runeBytes(input []rune) []byte {
+	b := make([]byte, 0, len(input)*utf8.UTFMax)
+	for _, r := range input {
+		b = utf8.AppendRune(b, r)
+	}
+	return b
+}
+
+// appendRune appends to the input, wiping the old input if it has to grow so
+// that no copies of it are left behind.
+func appendRune(input []rune, r rune) []rune {
+	if len(input) < cap(input) {
+		return append(input, r)
+	}
+	grown := make([]rune, len(input), 2*cap(input)+16)
+	copy(grown, input)
+	clear(input)
+	return append(grown, r)
+}
@@ -0,0 +1,125 @@
+package gluey_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+// recorder keeps everything written to the terminal, escape sequences included
+type recorder struct {
+	term *gluetest.Terminal
+	mut  sync.Mutex
+	raw  bytes.Buffer
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.mut.Lock()
+	r.raw.Write(p)
+	r.mut.Unlock()
+	return r.term.Write(p)
+}
+
+func (r *recorder) String() string {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.raw.String()
+}
+
+func recordedCtx(term *gluetest.Terminal, opts ...gluey.Option) (*gluey.Ctx, *recorder) {
+	out := &recorder{term: term}
+	return term.Ctx(append(opts, gluey.WithOutput(out), gluey.WithErrOutput(out))...), out
+}
+
+func TestAskPasswordIsNotEchoed(t *testing.T) {
+	for _, mask := range []rune{0, '*'} {
+		term := gluetest.NewTerminal()
+		ctx, out := recordedCtx(term)
+		// none of the characters of the secret are in the label or the escape
+		// sequences, so that any echo of them can be found
+		term.Type("z", "q", "x", "j", "y", gluetest.KeyEnter)
+		if password, err := ctx.AskPassword("Password?", gluey.WithMask(mask)); err != nil || password != "zqxjy" {
+			t.Fatalf("AskPassword = %q, %v", password, err)
+		}
+		for _, r := range "zqxjy" {
+			if strings.ContainsRune(out.String(), r) {
+				t.Errorf("typed %q was written to the terminal: %q", r, out.String())
+			}
+		}
+	}
+}
+
+func TestAskPasswordMask(t *testing.T) {
+	term := gluetest.NewTerminal()
+	done := make(chan string)
+	go func() {
+		password, _ := term.Ctx().AskPassword("Password?", gluey.WithMask('*'))
+		done <- password
+	}()
+	term.Type("a", "b", "c")
+	waitFor(t, term, "Password? ***")
+	term.Type(gluetest.KeyBackspace)
+	waitForScreen(t, term, "? Password? **")
+	term.Type(gluetest.KeyEnter)
+	if password := <-done; password != "ab" {
+		t.Errorf("AskPassword = %q", password)
+	}
+}
+
+func TestAskPasswordConfirmation(t *testing.T) {
+	term := gluetest.NewTerminal()
+	done := make(chan string)
+	go func() {
+		password, _ := term.Ctx().AskPassword("Password?", gluey.WithConfirmation())
+		done <- password
+	}()
+	term.Type("one", gluetest.KeyEnter, "two", gluetest.KeyEnter)
+	waitFor(t, term, "passwords do not match")
+	term.Type("pass", gluetest.KeyEnter, "pass", gluetest.KeyEnter)
+	if password := <-done; password != "pass" {
+		t.Errorf("AskPassword = %q", password)
+	}
+}
+
+func TestAskPasswordStrength(t *testing.T) {
+	term := gluetest.NewTerminal()
+	done := make(chan string)
+	go func() {
+		password, _ := term.Ctx().AskPassword("Password?", gluey.WithStrength(gluey.BasicPasswordChecker(8)))
+		done <- password
+	}()
+	term.Type("short", gluetest.KeyEnter)
+	waitFor(t, term, "must be at least 8 characters")
+	term.Type("er-Pass1", gluetest.KeyEnter)
+	if password := <-done; password != "shorter-Pass1" {
+		t.Errorf("AskPassword = %q", password)
+	}
+}
+
+func TestAskPasswordFuncZeroesPassword(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("secret", gluetest.KeyEnter)
+	var kept []byte
+	err := term.Ctx().AskPasswordFunc("Password?", func(password []byte) error {
+		if string(password) != "secret" {
+			t.Errorf("password = %q", password)
+		}
+		kept = password
+		return nil
+	})
+	if err != nil || !bytes.Equal(kept, make([]byte, len(kept))) {
+		t.Errorf("AskPasswordFunc = %v, left %q", err, kept)
+	}
+}
+
+func TestAskPasswordInterrupted(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("se", gluetest.KeyCtrlC)
+	if _, err := term.Ctx().AskPassword("Password?"); err != gluey.ErrInterrupted {
+		t.Errorf("AskPassword = %v", err)
+	}
+}
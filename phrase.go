@@ -0,0 +1,137 @@
+package gluey
+
+import (
+	"cmp"
+	"unicode"
+
+	"github.com/chzyer/readline"
+	"github.com/tanema/gluey/term"
+)
+
+const phraseTemplate = `{{.Prefix}}
+{{- if .Cancelled -}}
+	{{ iconBad }} {{ .Label }} {{ printf "(%v)" .Status | faint }}
+{{- else if .Done -}}
+	{{ iconGood }} {{ .Label }} {{ "(confirmed)" | faint }}
+{{- else -}}
+{{ iconWarn }} {{ .Label }}
+{{ .Prefix }}{{ "Type" | faint }} {{ .Phrase | yellow | bold }} {{ "to confirm, or Esc to cancel" | faint }}
+{{ .Prefix }}{{ blue ">" }} {{ .Matched | green }}{{ .Wrong | red }}{{ " " | underline }}
+{{- if .Complete }} {{ iconGood }}{{ end }}
+{{- end }}`
+
+// phrasePrompt asks for a phrase to be typed out exactly
+type phrasePrompt struct {
+	ctx       *Ctx
+	label     string
+	phrase    []rune
+	input     []rune
+	done      bool
+	cancelErr error
+}
+
+// ConfirmPhrase asks the user to confirm a destructive action by typing out the
+// phrase exactly. The input is checked against the phrase as it is typed and nil
+// is returned once it matches. If the input does not match when enter is
+// pressed, or Esc is pressed, ErrNotConfirmed is returned.
+func (ctx *Ctx) ConfirmPhrase(label, phrase string) error {
+	if ctx.nonInteractive {
+		ctx.Println(Fmt(`{{iconWarn}} {{.}}`, label))
+		answer, err := ctx.answer(label, "")
+		if err != nil {
+			return err
+		} else if answer != phrase {
+			return &AnswerError{Label: label, Answer: answer, Err: ErrNotConfirmed}
+		}
+		return nil
+	}
+	return (&phrasePrompt{ctx: ctx, label: label, phrase: []rune(phrase)}).run()
+}
+
+func ConfirmPhrase(label, phrase string) error {
+	return New().ConfirmPhrase(label, phrase)
+}
+
+func (pp *phrasePrompt) run() error {
+	rl, err := pp.ctx.newLineReader(&readline.Config{
+		HistoryLimit:   -1,
+		UniqueEditLine: true,
+	})
+	if err != nil {
+		return err
+	}
+	rl.stdin.namedKeys = true
+	return rl.readKeys(pp)
+}
+
+func (pp *phrasePrompt) finished() bool {
+	return pp.done || pp.cancelErr != nil
+}
+
+func (pp *phrasePrompt) cancel(err error) error {
+	pp.cancelErr = cmp.Or(pp.cancelErr, err)
+	return pp.cancelErr
+}
+
+func (pp *phrasePrompt) listen(key rune) {
+	switch key {
+	case readline.CharInterrupt:
+		pp.cancelErr = ErrInterrupted
+	case readline.CharDelete:
+		if len(pp.input) == 0 {
+			pp.cancelErr = ErrAborted
+		}
+	case KeyEsc:
+		pp.cancelErr = ErrNotConfirmed
+	case readline.CharEnter:
+		if pp.matched() == len(pp.phrase) && len(pp.input) == len(pp.phrase) {
+			pp.done = true
+		} else {
+			pp.cancelErr = ErrNotConfirmed
+		}
+	case readline.CharBackspace, readline.CharCtrlH:
+		if len(pp.input) > 0 {
+			pp.input = pp.input[:len(pp.input)-1]
+		}
+	case readline.CharKill, readline.CharCtrlU:
+		pp.input = pp.input[:0]
+	default:
+		if unicode.IsPrint(key) {
+			pp.input = append(pp.input, key)
+		}
+	}
+}
+
+// matched is how much of the input matches the start of the phrase
+func (pp *phrasePrompt) matched() int {
+	i := 0
+	for i < len(pp.input) && i < len(pp.phrase) && pp.input[i] == pp.phrase[i] {
+		i++
+	}
+	return i
+}
+
+func (pp *phrasePrompt) render(sb *term.ScreenBuf) {
+	matched := pp.matched()
+	status := "cancelled"
+	if pp.cancelErr == ErrNotConfirmed {
+		status = "not confirmed"
+	}
+	sb.WriteTmpl(phraseTemplate, struct {
+		Prefix, Label, Phrase string
+		Matched, Wrong        string
+		Status                string
+		Complete              bool
+		Done, Cancelled       bool
+	}{
+		Prefix:    pp.ctx.Prefix(),
+		Label:     pp.label,
+		Phrase:    string(pp.phrase),
+		Matched:   string(pp.input[:matched]),
+		Wrong:     string(pp.input[matched:]),
+		Status:    status,
+		Complete:  matched == len(pp.phrase) && len(pp.input) == len(pp.phrase),
+		Done:      pp.done,
+		Cancelled: pp.cancelErr != nil,
+	})
+}
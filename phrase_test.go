@@ -0,0 +1,61 @@
+package gluey_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestConfirmPhrase(t *testing.T) {
+	cases := []struct {
+		name   string
+		keys   []string
+		want   error
+		screen string
+	}{
+		{"typed", []string{"prod", gluetest.KeyEnter}, nil, "(confirmed)"},
+		{"corrected", []string{"pord", gluetest.KeyBackspace, gluetest.KeyBackspace, gluetest.KeyBackspace, "rod", gluetest.KeyEnter}, nil, "(confirmed)"},
+		{"wrong", []string{"pro", gluetest.KeyEnter}, gluey.ErrNotConfirmed, "(not confirmed)"},
+		{"too long", []string{"prods", gluetest.KeyEnter}, gluey.ErrNotConfirmed, "(not confirmed)"},
+		{"esc", []string{"pr", gluetest.KeyEsc}, gluey.ErrNotConfirmed, "(not confirmed)"},
+		{"interrupted", []string{"pr", gluetest.KeyCtrlC}, gluey.ErrInterrupted, "(cancelled)"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			term := gluetest.NewTerminal()
+			term.Type(c.keys...)
+			if err := term.Ctx().ConfirmPhrase("Delete the database?", "prod"); err != c.want {
+				t.Errorf("ConfirmPhrase = %v, want %v", err, c.want)
+			}
+			if screen := term.Screen(); !strings.Contains(screen, "Delete the database? "+c.screen) {
+				t.Errorf("screen = %q", screen)
+			}
+		})
+	}
+}
+
+func TestConfirmPhraseShowsPhrase(t *testing.T) {
+	term := gluetest.NewTerminal()
+	done := make(chan error)
+	go func() {
+		done <- term.Ctx().ConfirmPhrase("Delete?", "prod")
+	}()
+	waitFor(t, term, "Type prod to confirm")
+	term.Type("pr")
+	waitFor(t, term, "> pr")
+	term.Type(gluetest.KeyEsc)
+	<-done
+}
+
+func TestConfirmPhraseNonInteractive(t *testing.T) {
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Delete?": "prod", "Drop?": "nope"}))
+	if err := ctx.ConfirmPhrase("Delete?", "prod"); err != nil {
+		t.Errorf("ConfirmPhrase = %v", err)
+	}
+	if err := ctx.ConfirmPhrase("Drop?", "prod"); !errors.Is(err, gluey.ErrNotConfirmed) {
+		t.Errorf("ConfirmPhrase with the wrong answer = %v", err)
+	}
+}
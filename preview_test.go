@@ -0,0 +1,55 @@
+package gluey_test
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestSelectPreview(t *testing.T) {
+	term := gluetest.NewTerminal()
+	var calls atomic.Int32
+	preview := gluey.WithPreview(func(index int) string {
+		calls.Add(1)
+		return fmt.Sprintf("preview of %v\nsecond line\n", index)
+	})
+	done := make(chan string)
+	go func() {
+		_, item, _ := term.Ctx().Select("Pick", []string{"a", "b"}, preview)
+		done <- item
+	}()
+	waitFor(t, term, "┃ preview of 1")
+	term.Type(gluetest.KeyDown)
+	waitFor(t, term, "┃ preview of 2")
+	term.Type(gluetest.KeyUp)
+	waitFor(t, term, "┃ preview of 1")
+	term.Type(gluetest.KeyEnter)
+	if item := <-done; item != "a" {
+		t.Errorf("Select = %q", item)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("preview called %v times, want once for each item", calls.Load())
+	}
+	if strings.Contains(term.Screen(), "preview") {
+		t.Errorf("preview left on the screen:\n%s", term.Screen())
+	}
+}
+
+func TestSelectSlowPreview(t *testing.T) {
+	term := gluetest.NewTerminal()
+	release := make(chan struct{})
+	defer close(release)
+	preview := gluey.WithPreview(func(index int) string {
+		<-release
+		return "never shown"
+	})
+	term.Type(gluetest.KeyDown, gluetest.KeyDown, gluetest.KeyEnter)
+	_, item, err := term.Ctx().Select("Pick", []string{"a", "b", "c"}, preview)
+	if err != nil || item != "c" {
+		t.Errorf("Select = %q, %v", item, err)
+	}
+}
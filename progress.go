@@ -50,7 +50,7 @@ func (ctx *Ctx) Progress(title string, total float64) *Bar {
 
 // NewProgressGroup will create a new progress bar group the will track multiple bars
 func (ctx *Ctx) NewProgressGroup() *ProgressGroup {
-	return &ProgressGroup{ctx: ctx, screen: term.NewScreenBuf(ctx.Writer())}
+	return &ProgressGroup{ctx: ctx, screen: ctx.newScreenBuf(ctx.Writer())}
 }
 
 // Add will add another bar to the group
@@ -113,7 +113,7 @@ func (bar *Bar) set(val float64) {
 	bar.Percent = strconv.Itoa(int((bar.current / bar.total) * 100))
 
 	percent := bar.current / bar.total
-	barwidth := term.Width() - (bar.ctx.Indent - 2) - len(bar.Title) - len(bar.Percent) - 4
+	barwidth := bar.ctx.width() - (bar.ctx.Indent - 2) - len(bar.Title) - len(bar.Percent) - 4
 	done := percent * float64(barwidth)
 	bar.DoneBar = strings.Repeat("█", int(done))
 	bar.RestBar = strings.Repeat("░", int(math.Max(float64(barwidth)-done, 0)))
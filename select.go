@@ -1,11 +1,15 @@
 package gluey
 
 import (
+	"cmp"
+	"context"
+	"fmt"
 	"io"
-	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
 	"unicode"
 
 	"github.com/chzyer/readline"
@@ -15,7 +19,9 @@ import (
 type selectMode int
 
 const selectTemplate = `{{.Prefix}}
-{{- if .Done -}}
+{{- if .Cancelled -}}
+	{{ iconBad }} {{ .Label }} {{ "(cancelled)" | faint }}
+{{- else if .Done -}}
 	{{ iconQ }} {{ .Label }} (You chose: {{ .Selected | italic }})
 {{- else -}}
 {{ iconQ }} {{ .Label }} {{ .HelpText | yellow }}
@@ -33,32 +39,61 @@ const selectTemplate = `{{.Prefix}}
 {{- end }}
 {{- end }}
 {{ range $index, $item := .Items -}}
+	{{- with index $.Headers $index -}}
+		{{ $.Prefix }}{{ . | bold }}
+{{ end -}}
 	{{ $.Prefix }}
 	{{- if eq $.Cursor $index -}}
-		{{ iconSel | blue }} {{ $item.Index | blue }} {{ if $.Multiple }}
+		{{ iconSel | blue }} {{ if not $.Tree }}{{ $item.Number | blue }} {{ end }}{{ if $.Multiple }}
 			{{- if .Chosen -}}
 				{{ iconChk | blue }}
 			{{- else -}}
 				{{ iconBox | blue }}
 			{{- end -}}
-		{{- end }} {{$item.Label | blue }}
-	{{- else }}  {{ $item.Index }} {{ if $.Multiple }}
+		{{- end }} {{ if $.Tree }}{{ $item.TreeGuide }}{{ end }}{{ if .Disabled -}}
+			{{ $item.Highlight "faint" }}{{ with .Reason }} {{ printf "(%v)" . | faint }}{{ end }}
+		{{- else -}}
+			{{ $item.Highlight "blue" }}
+		{{- end -}}
+		{{- with .Description }}
+{{ $.Prefix }}      {{ . | faint }}
+		{{- end -}}
+	{{- else }}  {{ if not $.Tree }}{{ $item.Number }} {{ end }}{{ if $.Multiple }}
 			{{- if .Chosen -}}
 				{{ iconChk }}
 			{{- else -}}
 				{{ iconBox }}
 			{{- end -}}
-		{{- end }} {{ if .Chosen -}}
-			{{ $item.Label | bold }}
+		{{- end }} {{ if $.Tree }}{{ $item.TreeGuide }}{{ end }}{{ if .Disabled -}}
+			{{ $item.Highlight "faint" }}{{ with .Reason }} {{ printf "(%v)" . | faint }}{{ end }}
+		{{- else if .Chosen -}}
+			{{ $item.Highlight "bold" }}
 		{{- else -}}
-			{{ $item.Label }}
+			{{ $item.Highlight "" }}
 		{{- end -}}
 	{{- end }}
 {{ else -}}
-	{{ .Prefix }}no results
-{{- end -}}
+	{{- if not .Loading -}}
+		{{ .Prefix }}no results
+{{ end -}}
+{{ end -}}
+{{- if .Loading -}}
+	{{ .Prefix }}{{ .Spinner | cyan }} {{ "Loading" | faint }}
+{{ end -}}
+{{- with .Err -}}
+	{{ $.Prefix }}{{ iconBad }} {{ . | red }}
+{{ end -}}
+{{- range .Preview -}}
+	{{ $.Prefix }}{{ "┃" | faint }} {{ . }}
+{{ end -}}
 {{- end -}}`
 
+// previewHeight is the most lines that a select preview pane will use
+const previewHeight = 10
+
+// previewLoading is shown in the preview pane while the preview is made
+var previewLoading = []string{Fmt(`{{ "Loading preview" | faint }}`, nil)}
+
 const (
 	normal selectMode = iota
 	selecting
@@ -78,31 +113,155 @@ type selectTemplateData struct {
 	SelectHelp  string
 	Mode        selectMode
 	Done        bool
+	Cancelled   bool
 	Multiple    bool
 	Cursor      int
+	Preview     []string
+	Headers     map[int]string
+	Tree        bool
+	Err         error
+	Loading     bool
+	Spinner     string
 }
 
 // Selector represents a list of items used to enable selections, they can be used as search engines, menus
 // or as a list of items in a cli based prompt.
 type Selector struct {
-	ctx        *Ctx
-	label      string
-	items      []*selectItem
-	searchTerm string
-	selectTerm string
-	mode       selectMode
-	done       bool
-	cursor     int
-	multiple   bool
-	scope      []*selectItem
-	size       int
-	start      int
+	ctx          *Ctx
+	label        string
+	items        []*selectItem
+	searchTerm   string
+	selectTerm   string
+	mode         selectMode
+	done         bool
+	cancelErr    error
+	cursor       int
+	multiple     bool
+	scope        []*selectItem
+	size         int
+	start        int
+	defaultIndex int
+	groups       []string
+	tree         bool
+	preview      func(index int) string
+	previews     map[int][]string
+	previewing   map[int]bool
+	minChoices   int
+	maxChoices   int
+	err          error
+	mut          sync.Mutex
+	screen       *term.ScreenBuf
+	loader       Loader
+	queryLoad    bool
+	loading      bool
+	loadErr      error
+	stopLoad     context.CancelFunc
+	spin         int
+	keymap       Keymap
 }
 
 type selectItem struct {
-	Label  string
-	Chosen bool
-	Index  int
+	Label       string
+	Chosen      bool
+	Index       int
+	Number      int
+	Disabled    bool
+	Reason      string
+	Description string
+	Group       string
+	Guide       string
+	Branch      bool
+	Expanded    bool
+	parent      *selectItem
+	matches     []int
+	score       int
+}
+
+// SelectOption configures a select prompt
+type SelectOption func(*Selector)
+
+// WithDefault starts the cursor on the item with the index, as returned by
+// Select, so that the user can just press enter.
+func WithDefault(index int) SelectOption {
+	return func(s *Selector) {
+		s.defaultIndex = index
+	}
+}
+
+// WithChecked will check the items with the indexes before prompting, this is
+// used by SelectMultiple to restore previous choices. A single select replaces
+// them with the item that is chosen.
+func WithChecked(indexes ...int) SelectOption {
+	return func(s *Selector) {
+		for _, index := range indexes {
+			if index > 0 && index <= len(s.items) {
+				s.items[index-1].Chosen = true
+			}
+		}
+	}
+}
+
+// WithDescription sets a description of the item with the index, that is shown
+// beneath the item when the cursor is on it.
+func WithDescription(index int, description string) SelectOption {
+	return func(s *Selector) {
+		if index > 0 && index <= len(s.items) {
+			s.items[index-1].Description = description
+		}
+	}
+}
+
+// WithPreview shows the output of preview for the item under the cursor in a
+// pane below the list. It is called with the index of the item, as returned by
+// Select, and only once for each item. It is called in the background so that
+// a slow preview does not hold up input.
+func WithPreview(preview func(index int) string) SelectOption {
+	return func(s *Selector) {
+		s.preview = preview
+		s.previews = map[int][]string{}
+		s.previewing = map[int]bool{}
+	}
+}
+
+// WithGroup shows the items with the indexes together under the header. Groups
+// are shown in the order that they are added, after any items without a group,
+// and the items are numbered in the order that they are shown.
+func WithGroup(header string, indexes ...int) SelectOption {
+	return func(s *Selector) {
+		s.groups = append(s.groups, header)
+		for _, index := range indexes {
+			if index > 0 && index <= len(s.items) {
+				s.items[index-1].Group = header
+			}
+		}
+	}
+}
+
+// WithMinChoices requires at least n items to be chosen before a multiple select
+// can be finished.
+func WithMinChoices(n int) SelectOption {
+	return func(s *Selector) {
+		s.minChoices = n
+	}
+}
+
+// WithMaxChoices allows at most n items to be chosen when a multiple select is
+// finished.
+func WithMaxChoices(n int) SelectOption {
+	return func(s *Selector) {
+		s.maxChoices = n
+	}
+}
+
+// WithDisabled will show the item with the index, and the reason why, but will
+// not allow it to be selected.
+func WithDisabled(index int, reason string) SelectOption {
+	return func(s *Selector) {
+		if index > 0 && index <= len(s.items) {
+			s.items[index-1].Disabled = true
+			s.items[index-1].Reason = reason
+		}
+	}
 }
 
 func convertSelectItems(in []string) []*selectItem {
@@ -114,158 +273,350 @@ func convertSelectItems(in []string) []*selectItem {
 }
 
 // Select will propt the user with a list and will allow them to select a single option
-func (ctx *Ctx) Select(label string, items []string) (int, string, error) {
-	indexes, items, err := newSelect(ctx, label, items).run()
+func (ctx *Ctx) Select(label string, items []string, opts ...SelectOption) (int, string, error) {
+	indexes, items, err := newSelect(ctx, label, items, opts...).run()
 	if len(indexes) == 0 && len(items) == 0 {
 		return -1, "", err
 	}
 	return indexes[0], items[0], err
 }
 
-func Select(label string, items []string) (int, string, error) {
-	return New().Select(label, items)
+func Select(label string, items []string, opts ...SelectOption) (int, string, error) {
+	return New().Select(label, items, opts...)
 }
 
 // SelectMultiple will propt the user with a list and will allow them to select multiple options
-func (ctx *Ctx) SelectMultiple(label string, items []string) ([]int, []string, error) {
-	return newMultipleSelect(ctx, label, items).run()
+func (ctx *Ctx) SelectMultiple(label string, items []string, opts ...SelectOption) ([]int, []string, error) {
+	return newMultipleSelect(ctx, label, items, opts...).run()
 }
 
-func SelectMultiple(label string, items []string) ([]int, []string, error) {
-	return New().SelectMultiple(label, items)
+func SelectMultiple(label string, items []string, opts ...SelectOption) ([]int, []string, error) {
+	return New().SelectMultiple(label, items, opts...)
 }
 
-func newSelect(ctx *Ctx, label string, items []string) *Selector {
+// SelectT will prompt the user with a list of values, labeled by labelFn, and
+// will allow them to select a single value.
+func SelectT[T any](ctx *Ctx, label string, items []T, labelFn func(T) string, opts ...SelectOption) (T, error) {
+	var choice T
+	indexes, _, err := newSelect(ctx, label, mapLabels(items, labelFn), opts...).run()
+	if len(indexes) > 0 {
+		choice = items[indexes[0]-1]
+	}
+	return choice, err
+}
+
+// SelectMultipleT will prompt the user with a list of values, labeled by
+// labelFn, and will allow them to select multiple values.
+func SelectMultipleT[T any](ctx *Ctx, label string, items []T, labelFn func(T) string, opts ...SelectOption) ([]T, error) {
+	indexes, _, err := newMultipleSelect(ctx, label, mapLabels(items, labelFn), opts...).run()
+	choices := make([]T, len(indexes))
+	for i, index := range indexes {
+		choices[i] = items[index-1]
+	}
+	return choices, err
+}
+
+func mapLabels[T any](items []T, labelFn func(T) string) []string {
+	labels := make([]string, len(items))
+	for i, item := range items {
+		labels[i] = labelFn(item)
+	}
+	return labels
+}
+
+func newSelect(ctx *Ctx, label string, items []string, opts ...SelectOption) *Selector {
 	sel := &Selector{
-		ctx:   ctx,
-		label: label,
-		items: convertSelectItems(items),
-		size:  term.Height() - (1 + ctx.Indent),
+		ctx:    ctx,
+		label:  label,
+		items:  convertSelectItems(items),
+		size:   ctx.height() - (1 + ctx.Indent),
+		keymap: DefaultKeymap(),
 	}
 	sel.cancelSearch()
+	sel.apply(opts)
 	return sel
 }
 
-func newMultipleSelect(ctx *Ctx, label string, items []string) *Selector {
+func newMultipleSelect(ctx *Ctx, label string, items []string, opts ...SelectOption) *Selector {
 	sel := &Selector{
 		ctx:      ctx,
 		label:    label,
 		items:    convertSelectItems(items),
 		multiple: true,
-		size:     term.Height() - (2 + ctx.Indent),
+		size:     ctx.height() - (2 + ctx.Indent),
+		keymap:   DefaultKeymap(),
 	}
 	sel.cancelSearch()
+	sel.apply(opts)
 	return sel
 }
 
+func (s *Selector) apply(opts []SelectOption) {
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.preview != nil {
+		s.size -= s.previewSize()
+	}
+	for _, item := range s.items {
+		if item.Description != "" {
+			s.size--
+			break
+		}
+	}
+	s.size = max(s.size-len(s.groups), 1)
+	s.number()
+	s.cancelSearch()
+	if s.defaultIndex > 0 && s.defaultIndex <= len(s.items) {
+		s.SetCursor(s.cursorFor(s.items[s.defaultIndex-1].Number))
+		s.skipDisabled()
+	}
+}
+
 // Run executes the select list. It displays the label and the list of items, asking the user to chose any
 // value within to list. Run will keep the prompt alive until it has been canceled from
 // the command prompt or it has received a valid value. It will return the value and an error if any
 // occurred during the select's execution.
 func (s *Selector) run() ([]int, []string, error) {
 	s.done = false
-	stdin := readline.NewCancelableStdin(os.Stdin)
-	c := &readline.Config{
-		Stdin:          stdin,
-		Stdout:         s.ctx.Writer(),
+	if s.ctx.nonInteractive {
+		if err := s.answer(); err != nil {
+			return []int{}, []string{}, err
+		}
+		indexes, items := s.Selected()
+		return indexes, items, nil
+	}
+	rl, err := s.ctx.newLineReader(&readline.Config{
 		HistoryLimit:   -1,
 		UniqueEditLine: true,
-	}
-	if err := c.Init(); err != nil {
-		return []int{}, []string{}, err
-	}
-
-	rl, err := readline.NewEx(c)
+	})
 	if err != nil {
 		return []int{}, []string{}, err
 	}
 
-	sb := term.NewScreenBuf(rl)
+	sb := s.ctx.newScreenBuf(rl)
+	s.screen = sb
+	rl.stdin.namedKeys = true
+	if s.loader != nil {
+		s.load("")
+	}
 
-	c.SetListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+	rl.SetListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		s.mut.Lock()
+		defer s.mut.Unlock()
 		s.listen(line, key)
 		s.render(sb)
-		if s.done {
-			stdin.Close()
+		if s.done || s.cancelErr != nil {
+			rl.stdin.Close()
 		}
 		return nil, 0, true
 	})
-	for !s.done && err == nil {
+	for !s.finished() && err == nil && s.ctx.Context().Err() == nil {
 		_, err = rl.Readline()
-		if err != nil && err == io.EOF {
+		if err == io.EOF && !s.ctx.keys().ended() {
 			err = nil
 		}
 	}
 	rl.Clean()
 	rl.Close()
-	time.Sleep(10 * time.Millisecond)
+	s.stopLoading()
+
+	if s.ctx.Context().Err() != nil {
+		sb.Clear()
+		return []int{}, []string{}, s.ctx.Context().Err()
+	}
+	if ierr := interruption(err); ierr != nil || s.cancelErr != nil {
+		s.mut.Lock()
+		defer s.mut.Unlock()
+		s.cancelErr = cmp.Or(s.cancelErr, ierr)
+		s.render(sb)
+		return []int{}, []string{}, s.cancelErr
+	}
 
 	indexes, items := s.Selected()
 	return indexes, items, err
 }
 
+// finished is true once the select is done or cancelled. It locks since the
+// listener and loaders change the state while run is reading keys.
+func (s *Selector) finished() bool {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.done || s.cancelErr != nil
+}
+
+// answer chooses the items from the context answers. An answer can be either the
+// label or the number of an item, as shown in the list, multiple selects take a
+// comma separated list as made by AnswerList.
+func (s *Selector) answer() error {
+	ans, err := s.ctx.answer(s.label, s.defaultAnswer())
+	if err != nil {
+		return err
+	}
+	choices := []string{ans}
+	if s.multiple {
+		choices = splitAnswer(ans)
+	}
+	for _, item := range s.items {
+		item.Chosen = false
+	}
+	for _, choice := range choices {
+		item := s.find(strings.TrimSpace(choice))
+		if item == nil {
+			return &AnswerError{Label: s.label, Answer: choice}
+		} else if item.Disabled {
+			return &AnswerError{Label: s.label, Answer: choice, Err: disabledError(item)}
+		}
+		item.Chosen = true
+	}
+	if err := s.checkLimits(); err != nil {
+		return &AnswerError{Label: s.label, Answer: ans, Err: err}
+	}
+	s.done = true
+	s.render(s.ctx.newScreenBuf(s.ctx.Writer()))
+	return nil
+}
+
+func disabledError(item *selectItem) error {
+	if item.Reason == "" {
+		return fmt.Errorf("%v is disabled", item.Label)
+	}
+	return fmt.Errorf("%v is disabled (%v)", item.Label, item.Reason)
+}
+
+// defaultAnswer is the checked items of a multiple select, or the item under the
+// cursor if it was set with WithDefault.
+func (s *Selector) defaultAnswer() string {
+	if s.multiple {
+		_, labels := s.Selected()
+		return AnswerList(labels...)
+	} else if s.defaultIndex > 0 && s.defaultIndex <= len(s.items) {
+		return s.items[s.defaultIndex-1].Label
+	}
+	return ""
+}
+
+func (s *Selector) find(choice string) *selectItem {
+	for _, item := range s.items {
+		if strings.EqualFold(item.Label, choice) || strconv.Itoa(item.Number) == choice {
+			return item
+		}
+	}
+	return nil
+}
+
 func (s *Selector) listen(line []rune, key rune) {
+	if key == 0 {
+		// readline calls the listener without a key each time that it starts to
+		// read, which must not search again or clear the error of the last key.
+		return
+	}
+	s.err = nil
+	action, bound := s.keymap[key]
 	switch s.mode {
 	case normal:
-		switch key {
-		case readline.CharNext, 'j':
-			s.next()
-		case readline.CharPrev, 'k':
-			s.prev()
-		case 'f', '/':
-			s.mode = filtering
-		case readline.CharEnter, ' ':
-			s.selectItem(s.cursor)
-		default:
+		if key == readline.CharDelete && (!bound || action == ActionExit) {
+			s.cancelErr = ErrAborted
+		} else if bound {
+			s.do(action)
+		} else {
 			s.keyedSelectItem(key)
 		}
 	case selecting:
-		switch key {
-		case readline.CharEsc, 'j', 'k', readline.CharNext, readline.CharPrev, readline.CharDelete:
+		switch {
+		case action == ActionUp, action == ActionDown, action == ActionExit:
 			s.mode = normal
 			s.selectTerm = ""
-		case readline.CharBackspace:
+		case key == readline.CharBackspace:
 			if len(s.selectTerm) > 0 {
 				s.selectTerm = s.selectTerm[:len(s.selectTerm)-1]
 				cur, err := strconv.Atoi(s.selectTerm)
 				if err == nil {
-					s.SetCursor(cur - 1)
+					s.SetCursor(s.cursorFor(cur))
 				}
 			} else {
 				s.mode = normal
 			}
-		case readline.CharEnter, ' ':
+		case action == ActionChoose:
 			s.selectItem(s.cursor)
 		default:
 			s.keyedSelectItem(key)
 		}
 	case filtering:
-		switch key {
-		case readline.CharNext:
-			s.next()
-		case readline.CharPrev:
-			s.prev()
-		case readline.CharEsc, readline.CharDelete:
-			s.cancelSearch()
-		case readline.CharBackspace:
+		switch {
+		case key == readline.CharBackspace:
 			if len(s.searchTerm) > 0 {
 				s.searchTerm = s.searchTerm[:len(s.searchTerm)-1]
 				s.search(s.searchTerm)
 			} else {
 				s.cancelSearch()
 			}
-		case readline.CharEnter, ' ':
-			s.selectItem(s.cursor)
-		default:
+		case bound && !isFilterKey(key):
+			s.do(action)
+		case len(line) > 0:
 			s.searchTerm += string(line)
 			s.search(s.searchTerm)
 		}
 	}
 }
 
+// do performs the action that a key is bound to
+func (s *Selector) do(action KeyAction) {
+	switch action {
+	case ActionUp:
+		s.prev()
+	case ActionDown:
+		s.next()
+	case ActionHome:
+		s.SetCursor(0)
+		s.skipDisabled()
+	case ActionEnd:
+		s.SetCursor(len(s.scope) - 1)
+		if s.cursor < len(s.scope) && s.scope[s.cursor].Disabled {
+			s.prev()
+		}
+	case ActionPageUp:
+		s.SetCursor(max(s.cursor-s.size, 0))
+		s.skipDisabled()
+	case ActionPageDown:
+		s.SetCursor(s.cursor + s.size)
+		s.skipDisabled()
+	case ActionExpand:
+		s.expand(true)
+	case ActionCollapse:
+		s.expand(false)
+	case ActionChoose:
+		s.selectItem(s.cursor)
+	case ActionFilter:
+		s.mode = filtering
+	case ActionExit:
+		if s.mode == filtering {
+			s.cancelSearch()
+		}
+	case ActionAll:
+		s.chooseAll(func(*selectItem) bool { return true })
+	case ActionNone:
+		s.chooseAll(func(*selectItem) bool { return false })
+	case ActionInvert:
+		s.chooseAll(func(item *selectItem) bool { return !item.Chosen })
+	}
+}
+
+// isFilterKey is true for keys that are typed into the filter rather than used
+// for their binding.
+func isFilterKey(key rune) bool {
+	return unicode.IsLetter(key) || unicode.IsDigit(key) || unicode.IsPunct(key) || unicode.IsSymbol(key)
+}
+
 func (s *Selector) keyedSelectItem(key rune) {
 	if !unicode.IsNumber(key) {
 		return
+	} else if s.tree {
+		// trees are not numbered, but 0 will still finish a multiple select
+		if key == '0' {
+			s.selectItem(-1)
+		}
+		return
 	}
 	if len(s.items) > 9 {
 		cur, err := strconv.Atoi(s.selectTerm + string(key))
@@ -274,45 +625,122 @@ func (s *Selector) keyedSelectItem(key rune) {
 		}
 		s.mode = selecting
 		s.selectTerm += string(key)
-		s.SetCursor(cur - 1)
+		s.SetCursor(s.cursorFor(cur))
 		return
 	}
 	cur, err := strconv.Atoi(string(key))
 	if err != nil {
 		return
 	}
-	s.selectItem(cur - 1)
+	s.selectItem(s.cursorFor(cur))
+}
+
+// cursorFor finds the cursor position of the item with the number shown in the
+// list.
+func (s *Selector) cursorFor(number int) int {
+	for i, item := range s.scope {
+		if item.Number == number {
+			return i
+		}
+	}
+	return number - 1
+}
+
+// number numbers the items in the order that they are shown, which is not the
+// order of their indexes when they are grouped.
+func (s *Selector) number() {
+	items := slices.Clone(s.items)
+	slices.SortStableFunc(items, func(a, b *selectItem) int {
+		return cmp.Compare(slices.Index(s.groups, a.Group), slices.Index(s.groups, b.Group))
+	})
+	for i, item := range items {
+		item.Number = i + 1
+	}
 }
 
 func (s *Selector) selectItem(cursor int) {
-	if len(s.scope) == 0 && cursor < 0 && cursor >= len(s.scope) {
+	if cursor < 0 || cursor >= len(s.scope) {
+		// selecting -1 will finish a multiple select with the Done option
+		s.SetCursor(cursor)
 		return
 	}
-	s.scope[cursor].Chosen = !s.scope[cursor].Chosen
 	s.SetCursor(cursor)
-	if !s.multiple {
-		s.done = true
+	if s.scope[cursor].Disabled {
+		return
+	}
+	if s.multiple {
+		s.scope[cursor].Chosen = !s.scope[cursor].Chosen
+		return
 	}
+	for _, item := range s.items {
+		item.Chosen = false
+	}
+	s.scope[cursor].Chosen = true
+	s.done = true
 }
 
 func (s *Selector) search(term string) {
 	term = strings.Trim(term, " ")
 	s.cursor = 0
 	s.start = 0
+	if s.queryLoad && s.loader != nil {
+		s.load(term)
+		return
+	}
+	matcher := s.ctx.matcher
+	if matcher == nil {
+		matcher = FuzzyMatcher
+	}
 	scope := []*selectItem{}
 	for _, item := range s.items {
-		if strings.Contains(strings.ToLower(item.Label), strings.ToLower(term)) {
+		item.score, item.matches = 0, nil
+		if term == "" {
+			scope = append(scope, item)
+		} else if score, matches, ok := matcher(term, item.Label); ok {
+			item.score, item.matches = score, matches
 			scope = append(scope, item)
 		}
 	}
-	s.scope = scope
+	if s.tree {
+		s.scope = s.treeScope(scope, term != "")
+		if len(scope) > 0 {
+			s.SetCursor(slices.Index(s.scope, scope[0]))
+		}
+		s.skipDisabled()
+		return
+	}
+	s.scope = s.sorted(scope)
+	s.skipDisabled()
+}
+
+// sorted orders the items by their group and then by their match score, keeping
+// the original order otherwise.
+func (s *Selector) sorted(items []*selectItem) []*selectItem {
+	sort.SliceStable(items, func(i, j int) bool {
+		groupI, groupJ := slices.Index(s.groups, items[i].Group), slices.Index(s.groups, items[j].Group)
+		if groupI != groupJ {
+			return groupI < groupJ
+		}
+		return items[i].score > items[j].score
+	})
+	return items
 }
 
 func (s *Selector) cancelSearch() {
 	s.mode = normal
 	s.cursor = 0
 	s.start = 0
-	s.scope = s.items
+	for _, item := range s.items {
+		item.score, item.matches = 0, nil
+	}
+	s.scope = s.sorted(slices.Clone(s.items))
+	if s.tree {
+		s.scope = s.treeScope(s.items, false)
+	}
+	s.skipDisabled()
+	if s.queryLoad && s.loader != nil && s.screen != nil {
+		s.load("")
+	}
 }
 
 // SetCursor will set the list cursor to a single item in the list
@@ -324,23 +752,145 @@ func (s *Selector) SetCursor(i int) {
 		s.start = s.cursor - s.size + 1
 	}
 	if s.multiple && i == -1 && len(s.scope) > 0 {
+		s.finish()
+	}
+}
+
+// finish will complete a multiple select if the amount of chosen items is
+// within the limits, otherwise the error is shown.
+func (s *Selector) finish() {
+	if s.err = s.checkLimits(); s.err == nil {
 		s.done = true
 	}
 }
 
-func (s *Selector) next() {
-	if s.cursor >= len(s.scope)-1 {
-		s.SetCursor(0)
-	} else {
-		s.SetCursor(s.cursor + 1)
+func (s *Selector) checkLimits() error {
+	count := len(s.selectedItems())
+	if count < s.minChoices {
+		return fmt.Errorf("choose at least %d", s.minChoices)
+	} else if s.maxChoices > 0 && count > s.maxChoices {
+		return fmt.Errorf("choose at most %d", s.maxChoices)
+	}
+	return nil
+}
+
+// chooseAll will check, uncheck or invert all of the selectable items that are
+// in scope.
+func (s *Selector) chooseAll(chosen func(item *selectItem) bool) {
+	if !s.multiple {
+		return
+	}
+	for _, item := range s.scope {
+		if !item.Disabled {
+			item.Chosen = chosen(item)
+		}
 	}
 }
 
+func (s *Selector) next() {
+	s.move(1)
+}
+
 func (s *Selector) prev() {
-	if s.cursor <= 0 {
-		s.SetCursor(len(s.scope) - 1)
-	} else {
-		s.SetCursor(s.cursor - 1)
+	s.move(-1)
+}
+
+// move will move the cursor in the direction to the next item that is not
+// disabled, wrapping around the ends of the list.
+func (s *Selector) move(dir int) {
+	cursor := s.cursor
+	for range s.scope {
+		cursor = (cursor + dir + len(s.scope)) % len(s.scope)
+		if !s.scope[cursor].Disabled {
+			s.SetCursor(cursor)
+			return
+		}
+	}
+}
+
+// skipDisabled moves the cursor off of a disabled item
+func (s *Selector) skipDisabled() {
+	if s.cursor < len(s.scope) && s.scope[s.cursor].Disabled {
+		s.next()
+	}
+}
+
+// Highlight renders the label in the style, with the characters that matched
+// the filter underlined.
+func (item *selectItem) Highlight(style string) string {
+	runes := []rune(item.Label)
+	matched := make([]bool, len(runes))
+	for _, i := range item.matches {
+		if i >= 0 && i < len(runes) {
+			matched[i] = true
+		}
+	}
+	var out strings.Builder
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+		text := string(runes[start:end])
+		if style != "" {
+			text = term.Style(style, text)
+		}
+		if matched[start] {
+			text = term.Style("underline", text)
+		}
+		out.WriteString(text)
+		start = end
+	}
+	return out.String()
+}
+
+// headers finds the group headers to show above the visible items, which is at
+// the start of each group and at the top of the list.
+func (s *Selector) headers() map[int]string {
+	headers := map[int]string{}
+	group := ""
+	for i, item := range s.scopedItems() {
+		if item.Group != "" && (i == 0 || item.Group != group) {
+			headers[i] = item.Group
+		}
+		group = item.Group
+	}
+	return headers
+}
+
+// previewSize is the amount of lines that the preview pane may use
+func (s *Selector) previewSize() int {
+	return min(previewHeight, s.ctx.height()/3)
+}
+
+// previewLines renders the preview of the item under the cursor. The preview is
+// made in the background the first time, rendering again once it is ready.
+func (s *Selector) previewLines() []string {
+	if s.preview == nil || s.done || s.cursor >= len(s.scope) {
+		return nil
+	}
+	index := s.scope[s.cursor].Index
+	if lines, ok := s.previews[index]; ok {
+		return lines
+	} else if !s.previewing[index] && s.screen != nil {
+		s.previewing[index] = true
+		go s.makePreview(index, s.previewSize())
+	}
+	return previewLoading
+}
+
+// makePreview calls the preview for the item with the index and renders it, if
+// the select is still shown.
+func (s *Selector) makePreview(index, size int) {
+	lines := strings.Split(strings.TrimRight(s.preview(index), "\n"), "\n")
+	if len(lines) > size {
+		lines = lines[:size]
+	}
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.previews[index] = lines
+	if s.screen != nil {
+		s.render(s.screen)
 	}
 }
 
@@ -393,21 +943,24 @@ func (s *Selector) render(sb *term.ScreenBuf) {
 		Prefix:      s.ctx.Prefix(),
 		Label:       s.label,
 		Items:       s.scopedItems(),
-		HelpText:    Fmt("(Choose with ↑ ↓ "+term.ReturnLabel+", filter with 'f')", nil),
-		FilterHelp:  "Ctrl-D, Esc anytime or Backspace to exit",
-		SelectHelp:  "Ctrl-D, Esc or up/down anytime to exit",
+		HelpText:    s.keymap.helpText(s.multiple),
+		FilterHelp:  strings.TrimPrefix(s.keymap.exitHelp()+" anytime or Backspace to exit", " anytime or "),
+		SelectHelp:  strings.TrimPrefix(s.keymap.exitHelp()+" or up/down anytime to exit", " or "),
 		SelectTerm:  "Select: " + s.selectTerm,
 		SearchTerm:  "Filter: " + s.searchTerm,
 		Selected:    s.selectedLabel(),
 		SelectCount: len(s.selectedItems()),
 		Mode:        s.mode,
 		Done:        s.done,
+		Cancelled:   s.cancelErr != nil,
 		Multiple:    s.multiple,
 		Cursor:      s.cursor - s.start,
-	}
-
-	if s.multiple {
-		templateData.HelpText = strings.Replace(templateData.HelpText, "Choose", "Toggle", 1)
+		Preview:     s.previewLines(),
+		Headers:     s.headers(),
+		Tree:        s.tree,
+		Err:         cmp.Or(s.err, s.loadErr),
+		Loading:     s.loading,
+		Spinner:     string(term.SpinGlyphs[s.spin%len(term.SpinGlyphs)]),
 	}
 
 	sb.WriteTmpl(template, templateData)
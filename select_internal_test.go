@@ -0,0 +1,26 @@
+package gluey
+
+import (
+	"testing"
+
+	"github.com/tanema/gluey/term"
+)
+
+func TestHighlight(t *testing.T) {
+	item := &selectItem{Label: "VSCode", matches: []int{0, 2}}
+	want := term.Style("underline", term.Style("blue", "V")) + term.Style("blue", "S") +
+		term.Style("underline", term.Style("blue", "C")) + term.Style("blue", "ode")
+	if got := item.Highlight("blue"); got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+	if got, want := (&selectItem{Label: "Vim"}).Highlight(""), "Vim"; got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkHighlight(b *testing.B) {
+	item := &selectItem{Label: "Visual Studio Code", matches: []int{0, 7, 14}}
+	for b.Loop() {
+		item.Highlight("bold")
+	}
+}
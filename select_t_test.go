@@ -0,0 +1,43 @@
+package gluey_test
+
+import (
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+type editor struct {
+	name string
+	year int
+}
+
+var editors = []editor{{"Vim", 1991}, {"Emacs", 1976}, {"VSCode", 2015}}
+
+func editorName(e editor) string { return e.name }
+
+func TestSelectT(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyDown, gluetest.KeyEnter)
+	chosen, err := gluey.SelectT(term.Ctx(), "Editor?", editors, editorName)
+	if err != nil || chosen != editors[1] {
+		t.Errorf("SelectT = %v, %v", chosen, err)
+	}
+}
+
+func TestSelectMultipleT(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("1", "3", "0")
+	chosen, err := gluey.SelectMultipleT(term.Ctx(), "Editors?", editors, editorName)
+	if err != nil || len(chosen) != 2 || chosen[0] != editors[0] || chosen[1] != editors[2] {
+		t.Errorf("SelectMultipleT = %v, %v", chosen, err)
+	}
+}
+
+func TestSelectTInterrupted(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyCtrlC)
+	if chosen, err := gluey.SelectT(term.Ctx(), "Editor?", editors, editorName); err != gluey.ErrInterrupted || chosen != (editor{}) {
+		t.Errorf("SelectT = %v, %v", chosen, err)
+	}
+}
@@ -0,0 +1,172 @@
+package gluey_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestSelect(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyDown, gluetest.KeyEnter)
+	index, item, err := term.Ctx().Select("How hard?", []string{"Easy", "Hard"})
+	if err != nil || index != 2 || item != "Hard" {
+		t.Fatalf("Select = %v, %q, %v", index, item, err)
+	}
+	if got, want := term.Screen(), "? How hard? (You chose: Hard)"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestSelectWraps(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyUp, gluetest.KeyEnter)
+	if _, item, err := term.Ctx().Select("Pick", []string{"a", "b", "c"}); err != nil || item != "c" {
+		t.Errorf("Select = %q, %v", item, err)
+	}
+}
+
+func TestSelectNumberKey(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("3")
+	if index, _, err := term.Ctx().Select("Pick", []string{"a", "b", "c"}); err != nil || index != 3 {
+		t.Errorf("Select = %v, %v", index, err)
+	}
+}
+
+func TestSelectMultiple(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("1", "3", "0")
+	indexes, items, err := term.Ctx().SelectMultiple("Pick", []string{"a", "b", "c"})
+	if err != nil || len(indexes) != 2 || items[0] != "a" || items[1] != "c" {
+		t.Errorf("SelectMultiple = %v, %q, %v", indexes, items, err)
+	}
+}
+
+func TestSelectMultipleFilterKeepsCursor(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("f", "x", gluetest.KeyDown, gluetest.KeyEnter, gluetest.KeyDown, gluetest.KeyEnter, gluetest.KeyEsc, "0")
+	_, items, err := term.Ctx().SelectMultiple("Pick", []string{"x1", "x2", "x3", "y"})
+	if err != nil || !slices.Equal(items, []string{"x2", "x3"}) {
+		t.Errorf("SelectMultiple = %q, %v", items, err)
+	}
+}
+
+func TestSelectDefault(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyEnter)
+	if index, _, err := term.Ctx().Select("Pick", []string{"a", "b", "c"}, gluey.WithDefault(2)); err != nil || index != 2 {
+		t.Errorf("Select = %v, %v", index, err)
+	}
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{}))
+	if _, item, err := ctx.Select("Pick", []string{"a", "b", "c"}, gluey.WithDefault(3)); err != nil || item != "c" {
+		t.Errorf("non interactive Select = %q, %v", item, err)
+	}
+}
+
+func TestSelectMultipleChecked(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("1", "0")
+	_, items, err := term.Ctx().SelectMultiple("Pick", []string{"a", "b", "c"}, gluey.WithChecked(1, 3))
+	if err != nil || !slices.Equal(items, []string{"c"}) {
+		t.Errorf("SelectMultiple = %q, %v", items, err)
+	}
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{}))
+	if _, items, err := ctx.SelectMultiple("Pick", []string{"a", "b", "c"}, gluey.WithChecked(2)); err != nil || !slices.Equal(items, []string{"b"}) {
+		t.Errorf("non interactive SelectMultiple = %q, %v", items, err)
+	}
+}
+
+func TestSelectChecked(t *testing.T) {
+	for _, c := range []struct {
+		key   string
+		index int
+		item  string
+	}{
+		{gluetest.KeyEnter, 1, "a"},
+		{"3", 3, "c"},
+	} {
+		term := gluetest.NewTerminal()
+		term.Type(c.key)
+		index, item, err := term.Ctx().Select("Pick", []string{"a", "b", "c"}, gluey.WithChecked(1))
+		if err != nil || index != c.index || item != c.item {
+			t.Errorf("Select(%q) = %v, %q, %v, want %v, %q", c.key, index, item, err, c.index, c.item)
+		}
+	}
+}
+
+func TestSelectDisabled(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("2", gluetest.KeyDown, gluetest.KeyEnter)
+	disabled := gluey.WithDisabled(2, "sold out")
+	if _, item, err := term.Ctx().Select("Pick", []string{"a", "b", "c"}, disabled); err != nil || item != "c" {
+		t.Errorf("Select = %q, %v", item, err)
+	}
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Pick": "b"}))
+	var answerErr *gluey.AnswerError
+	if _, _, err := ctx.Select("Pick", []string{"a", "b", "c"}, disabled); !errors.As(err, &answerErr) || !strings.Contains(err.Error(), "sold out") {
+		t.Errorf("non interactive Select of a disabled item = %v", err)
+	}
+}
+
+func TestSelectGroupsAreNumberedInOrder(t *testing.T) {
+	term := gluetest.NewTerminal()
+	items := []string{"apple", "carrot", "banana"}
+	groups := []gluey.SelectOption{gluey.WithGroup("Fruit", 1, 3), gluey.WithGroup("Vegetables", 2)}
+	done := make(chan int)
+	go func() {
+		index, _, _ := term.Ctx().Select("Pick", items, groups...)
+		done <- index
+	}()
+	waitFor(t, term, "Vegetables")
+	for _, line := range []string{"Fruit", "1  apple", "2  banana", "Vegetables", "3  carrot"} {
+		if !strings.Contains(term.Screen(), line) {
+			t.Errorf("%q not shown, screen:\n%s", line, term.Screen())
+		}
+	}
+	term.Type("2")
+	if index := <-done; index != 3 {
+		t.Errorf("Select with the 2 key = %v, want banana at index 3", index)
+	}
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Pick": "3"}))
+	if _, item, err := ctx.Select("Pick", items, groups...); err != nil || item != "carrot" {
+		t.Errorf("non interactive Select = %q, %v", item, err)
+	}
+}
+
+func TestSelectMultipleAllNoneInvert(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("a", "n", "1", "i", "0")
+	_, items, err := term.Ctx().SelectMultiple("Pick", []string{"a", "b", "c"}, gluey.WithDisabled(3, ""))
+	if err != nil || !slices.Equal(items, []string{"b"}) {
+		t.Errorf("SelectMultiple = %q, %v", items, err)
+	}
+}
+
+func TestSelectMultipleLimits(t *testing.T) {
+	term := gluetest.NewTerminal()
+	done := make(chan []string)
+	go func() {
+		_, items, _ := term.Ctx().SelectMultiple("Pick", []string{"a", "b", "c"}, gluey.WithMinChoices(1), gluey.WithMaxChoices(2))
+		done <- items
+	}()
+	waitFor(t, term, "Done")
+	term.Type("0")
+	waitFor(t, term, "choose at least 1")
+	term.Type("a", "0")
+	waitFor(t, term, "choose at most 2")
+	term.Type("2", "0")
+	if items := <-done; !slices.Equal(items, []string{"a", "c"}) {
+		t.Errorf("SelectMultiple = %q", items)
+	}
+
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Pick": "a,b,c"}))
+	var answerErr *gluey.AnswerError
+	if _, _, err := ctx.SelectMultiple("Pick", []string{"a", "b", "c"}, gluey.WithMaxChoices(2)); !errors.As(err, &answerErr) {
+		t.Errorf("non interactive SelectMultiple over the limit = %v", err)
+	}
+}
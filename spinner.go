@@ -67,7 +67,7 @@ func (spinner *Spin) Fail(err error) {
 
 // NewSpinGroup creates a new group of spinners to track multiple statuses
 func (ctx *Ctx) NewSpinGroup() *SpinGroup {
-	group := &SpinGroup{ctx: ctx, screen: term.NewScreenBuf(ctx.Writer())}
+	group := &SpinGroup{ctx: ctx, screen: ctx.newScreenBuf(ctx.Writer())}
 	go group.run()
 	return group
 }
@@ -20,14 +20,20 @@ const (
 	defaultTermHeight = 60
 )
 
-func size() (width, height int) {
+// SizeOf returns the width and height of the terminal connected to in. If in
+// is not a file the default size is returned.
+func SizeOf(in io.Reader, out io.Writer) (width, height int) {
+	f, ok := in.(*os.File)
+	if !ok {
+		return defaultTermWidth, defaultTermHeight
+	}
 	cmd := exec.Command("stty", "size")
-	cmd.Stdin = os.Stdin
-	out, err := cmd.Output()
+	cmd.Stdin = f
+	output, err := cmd.Output()
 	if err != nil {
 		return defaultTermWidth, defaultTermHeight
 	}
-	parts := strings.Split(strings.TrimRight(string(out), "\n"), " ")
+	parts := strings.Split(strings.TrimRight(string(output), "\n"), " ")
 	height, err = strconv.Atoi(parts[0])
 	if err != nil {
 		return defaultTermWidth, defaultTermHeight
@@ -41,12 +47,12 @@ func size() (width, height int) {
 
 // Width returns the column width of the terminal
 func Width() int {
-	w, _ := size()
+	w, _ := SizeOf(os.Stdin, os.Stdout)
 	return w
 }
 
 // Height returns the row size of the terminal
 func Height() int {
-	_, h := size()
+	_, h := SizeOf(os.Stdin, os.Stdout)
 	return h
 }
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package term
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSizeOf(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	// neither a reader that is not a file nor a file that is not a terminal has
+	// a size, so the default is used.
+	for _, in := range []io.Reader{strings.NewReader(""), devNull} {
+		if width, height := SizeOf(in, io.Discard); width != defaultTermWidth || height != defaultTermHeight {
+			t.Errorf("SizeOf(%T) = %v, %v", in, width, height)
+		}
+	}
+}
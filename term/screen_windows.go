@@ -41,33 +41,44 @@ func ClearLines(out io.Writer, linecount int) {
 
 // Width returns the column width of the terminal
 func Width() int {
-	w, _ := size()
+	w, _ := SizeOf(os.Stdin, os.Stdout)
 	return w
 }
 
 // Height returns the row size of the terminal
 func Height() int {
-	_, h := size()
+	_, h := SizeOf(os.Stdin, os.Stdout)
 	return h
 }
 
 func clearLine(out io.Writer) {
 	var w uint32
-	csbi := termInfo()
+	handle := outHandle(out)
+	csbi := termInfo(handle)
 	csbi.cursorPosition.x = 0
 	csbi.cursorPosition.y--
-	handle := syscall.Handle(os.Stdout.Fd())
 	procSetConsoleCursorPosition.Call(uintptr(handle), uintptr(*(*int32)(unsafe.Pointer(&csbi.cursorPosition))))
 	procFillConsoleOutputCharacter.Call(uintptr(handle), uintptr(' '), uintptr(csbi.size.x), uintptr(*(*int32)(unsafe.Pointer(&csbi.cursorPosition))), uintptr(unsafe.Pointer(&w)))
 }
 
-func termInfo() consoleScreenBufferInfo {
+func termInfo(handle syscall.Handle) consoleScreenBufferInfo {
 	var csbi consoleScreenBufferInfo
-	procGetConsoleScreenBufferInfo.Call(uintptr(syscall.Handle(os.Stdout.Fd())), uintptr(unsafe.Pointer(&csbi)))
+	procGetConsoleScreenBufferInfo.Call(uintptr(handle), uintptr(unsafe.Pointer(&csbi)))
 	return csbi
 }
 
-func size() (int, int) {
-	csbi := termInfo()
+// outHandle returns the console handle for out, falling back to stdout when out
+// is not a file.
+func outHandle(out io.Writer) syscall.Handle {
+	if f, ok := out.(*os.File); ok {
+		return syscall.Handle(f.Fd())
+	}
+	return syscall.Handle(os.Stdout.Fd())
+}
+
+// SizeOf returns the width and height of the console connected to out. If out
+// is not a file the size of the stdout console is returned.
+func SizeOf(in io.Reader, out io.Writer) (int, int) {
+	csbi := termInfo(outHandle(out))
 	return int(csbi.size.x - 1), int(csbi.size.y - 1)
 }
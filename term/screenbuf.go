@@ -92,6 +92,15 @@ func iconer(ic icon) func() string {
 	return func() string { return styler(ic.color)(ic.char) }
 }
 
+// Style applies the named style of the templates, like "blue" or "underline",
+// to the value without parsing a template.
+func Style(name string, v any) string {
+	if style, ok := funcMap[name].(func(any) string); ok {
+		return style(v)
+	}
+	return fmt.Sprint(v)
+}
+
 // Sprintf formats a string template and outputs console ready text
 func Sprintf(in string, data any) string {
 	return string(renderStringTemplate(in, data))
@@ -114,14 +123,21 @@ func renderStringTemplate(in string, data any) []byte {
 // clears and, moves up or down lines as needed to write the output to the
 // terminal using ANSI escape codes.
 type ScreenBuf struct {
-	w   io.Writer
-	buf *bytes.Buffer
-	mut sync.Mutex
+	w     io.Writer
+	buf   *bytes.Buffer
+	mut   sync.Mutex
+	width func() int
 }
 
 // NewScreenBuf creates and initializes a new ScreenBuf.
 func NewScreenBuf(w io.Writer) *ScreenBuf {
-	return &ScreenBuf{buf: &bytes.Buffer{}, w: w}
+	return &ScreenBuf{buf: &bytes.Buffer{}, w: w, width: Width}
+}
+
+// SetWidthFunc sets the function used to get the terminal width when wrapping
+// lines. By default the width of the stdin terminal is used.
+func (s *ScreenBuf) SetWidthFunc(width func() int) {
+	s.width = width
 }
 
 func (s *ScreenBuf) reset() {
@@ -138,7 +154,7 @@ func (s *ScreenBuf) WriteTmpl(in string, data any) {
 	defer s.mut.Unlock()
 	s.reset()
 	defer s.flush()
-	termWidth := Width()
+	termWidth := s.width()
 	tmpl := ansiwrap(renderStringTemplate(in, data), termWidth)
 	if tmpl[len(tmpl)-1] != '\n' {
 		tmpl = append(tmpl, '\n')
@@ -146,6 +162,14 @@ func (s *ScreenBuf) WriteTmpl(in string, data any) {
 	s.buf.Write(tmpl)
 }
 
+// Clear will erase everything that was last written to the screen
+func (s *ScreenBuf) Clear() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.reset()
+	s.flush()
+}
+
 func (s *ScreenBuf) flush() {
 	io.Copy(s.w, bytes.NewBuffer(s.buf.Bytes()))
 }
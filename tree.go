@@ -0,0 +1,140 @@
+package gluey
+
+import "slices"
+
+// TreeNode is a node of the tree shown by SelectTree. Nodes with children can be
+// expanded to show them, Expanded sets if they start expanded.
+type TreeNode struct {
+	Label    string
+	Children []*TreeNode
+	Expanded bool
+}
+
+// SelectTree will prompt the user with a tree and will allow them to select a
+// single node. Branches are expanded with → and collapsed with ←, and filtering
+// shows the matching nodes along with their ancestors. Select options index the
+// nodes in depth first order.
+func (ctx *Ctx) SelectTree(label string, nodes []*TreeNode, opts ...SelectOption) (*TreeNode, error) {
+	sel, all := newTreeSelect(newSelect(ctx, label, nil), nodes, opts)
+	indexes, _, err := sel.run()
+	if len(indexes) == 0 {
+		return nil, err
+	}
+	return all[indexes[0]-1], err
+}
+
+func SelectTree(label string, nodes []*TreeNode, opts ...SelectOption) (*TreeNode, error) {
+	return New().SelectTree(label, nodes, opts...)
+}
+
+// SelectTreeMultiple will prompt the user with a tree and will allow them to
+// select multiple nodes.
+func (ctx *Ctx) SelectTreeMultiple(label string, nodes []*TreeNode, opts ...SelectOption) ([]*TreeNode, error) {
+	sel, all := newTreeSelect(newMultipleSelect(ctx, label, nil), nodes, opts)
+	indexes, _, err := sel.run()
+	chosen := make([]*TreeNode, len(indexes))
+	for i, index := range indexes {
+		chosen[i] = all[index-1]
+	}
+	return chosen, err
+}
+
+func SelectTreeMultiple(label string, nodes []*TreeNode, opts ...SelectOption) ([]*TreeNode, error) {
+	return New().SelectTreeMultiple(label, nodes, opts...)
+}
+
+func newTreeSelect(sel *Selector, nodes []*TreeNode, opts []SelectOption) (*Selector, []*TreeNode) {
+	var all []*TreeNode
+	sel.tree = true
+	sel.items, all = flattenTree(nodes, nil, "", nil, nil)
+	sel.apply(opts)
+	return sel, all
+}
+
+// flattenTree lists the nodes depth first as select items, with the guides that
+// connect them to their parents.
+func flattenTree(nodes []*TreeNode, parent *selectItem, prefix string, items []*selectItem, all []*TreeNode) ([]*selectItem, []*TreeNode) {
+	for i, node := range nodes {
+		last := i == len(nodes)-1
+		item := &selectItem{
+			Label:    node.Label,
+			Index:    len(items) + 1,
+			Branch:   len(node.Children) > 0,
+			Expanded: node.Expanded,
+			parent:   parent,
+		}
+		childPrefix := ""
+		if parent != nil {
+			item.Guide, childPrefix = prefix+"├─ ", prefix+"│  "
+			if last {
+				item.Guide, childPrefix = prefix+"└─ ", prefix+"   "
+			}
+		}
+		items, all = append(items, item), append(all, node)
+		items, all = flattenTree(node.Children, item, childPrefix, items, all)
+	}
+	return items, all
+}
+
+// TreeGuide renders the guides and the expanded state of a tree item
+func (item *selectItem) TreeGuide() string {
+	guide := Fmt(`{{ . | faint }}`, item.Guide)
+	if item.Branch && item.Expanded {
+		return guide + "▾ "
+	} else if item.Branch {
+		return guide + "▸ "
+	}
+	return guide
+}
+
+// treeScope finds the items of a tree that should be shown. When filtering, the
+// matched items are shown along with their ancestors, otherwise items are shown
+// if all of their ancestors are expanded.
+func (s *Selector) treeScope(matched []*selectItem, filtering bool) []*selectItem {
+	scope := []*selectItem{}
+	for _, item := range s.items {
+		if filtering && hasMatch(item, matched) {
+			scope = append(scope, item)
+		} else if !filtering && item.visible() {
+			scope = append(scope, item)
+		}
+	}
+	return scope
+}
+
+// hasMatch is true if the item or any of its descendants were matched
+func hasMatch(item *selectItem, matched []*selectItem) bool {
+	for _, match := range matched {
+		for ; match != nil; match = match.parent {
+			if match == item {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (item *selectItem) visible() bool {
+	for parent := item.parent; parent != nil; parent = parent.parent {
+		if !parent.Expanded {
+			return false
+		}
+	}
+	return true
+}
+
+// expand will expand or collapse the branch under the cursor. Collapsing a
+// leaf or collapsed branch will move the cursor to its parent instead.
+func (s *Selector) expand(open bool) {
+	if !s.tree || s.mode == filtering || s.cursor >= len(s.scope) {
+		return
+	}
+	item := s.scope[s.cursor]
+	if item.Branch && item.Expanded != open {
+		item.Expanded = open
+	} else if !open && item.parent != nil {
+		item = item.parent
+	}
+	s.scope = s.treeScope(s.items, false)
+	s.SetCursor(slices.Index(s.scope, item))
+}
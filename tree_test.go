@@ -0,0 +1,76 @@
+package gluey_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func testTree() []*gluey.TreeNode {
+	return []*gluey.TreeNode{
+		{Label: "fruit", Children: []*gluey.TreeNode{{Label: "apple"}, {Label: "banana"}}},
+		{Label: "vegetables", Children: []*gluey.TreeNode{{Label: "carrot"}}},
+	}
+}
+
+func TestSelectTree(t *testing.T) {
+	term := gluetest.NewTerminal()
+	nodes := testTree()
+	done := make(chan *gluey.TreeNode)
+	go func() {
+		node, _ := term.Ctx().SelectTree("Pick", nodes)
+		done <- node
+	}()
+	waitFor(t, term, "vegetables")
+	if strings.Contains(term.Screen(), "apple") {
+		t.Errorf("collapsed branch shown:\n%s", term.Screen())
+	}
+	term.Type(gluetest.KeyRight)
+	waitFor(t, term, "apple")
+	term.Type(gluetest.KeyDown, gluetest.KeyDown, gluetest.KeyEnter)
+	if node := <-done; node != nodes[0].Children[1] {
+		t.Errorf("SelectTree = %v", node)
+	}
+}
+
+func TestSelectTreeCollapse(t *testing.T) {
+	term := gluetest.NewTerminal()
+	nodes := testTree()
+	nodes[0].Expanded = true
+	term.Type(gluetest.KeyDown, gluetest.KeyLeft, gluetest.KeyLeft, gluetest.KeyDown, gluetest.KeyEnter)
+	if node, err := term.Ctx().SelectTree("Pick", nodes); err != nil || node != nodes[1] {
+		t.Errorf("SelectTree = %v, %v", node, err)
+	}
+}
+
+func TestSelectTreeFilter(t *testing.T) {
+	term := gluetest.NewTerminal()
+	nodes := testTree()
+	done := make(chan *gluey.TreeNode)
+	go func() {
+		node, _ := term.Ctx().SelectTree("Pick", nodes)
+		done <- node
+	}()
+	waitFor(t, term, "vegetables")
+	term.Type("f", "c", "a", "r")
+	waitFor(t, term, "carrot")
+	if !strings.Contains(term.Screen(), "vegetables") || strings.Contains(term.Screen(), "fruit") {
+		t.Errorf("filter should show matches with their ancestors:\n%s", term.Screen())
+	}
+	term.Type(gluetest.KeyEnter)
+	if node := <-done; node != nodes[1].Children[0] {
+		t.Errorf("SelectTree = %v", node)
+	}
+}
+
+func TestSelectTreeMultiple(t *testing.T) {
+	term := gluetest.NewTerminal()
+	nodes := testTree()
+	term.Type(" ", gluetest.KeyRight, gluetest.KeyDown, gluetest.KeyDown, gluetest.KeyDown, " ", "0")
+	chosen, err := term.Ctx().SelectTreeMultiple("Pick", nodes)
+	if err != nil || len(chosen) != 2 || chosen[0] != nodes[0] || chosen[1] != nodes[1] {
+		t.Errorf("SelectTreeMultiple = %v, %v", chosen, err)
+	}
+}
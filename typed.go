@@ -0,0 +1,151 @@
+package gluey
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AskInt will prompt the user for an integer between minVal and maxVal
+// inclusive, re-prompting until a valid integer is given.
+func (ctx *Ctx) AskInt(label string, minVal, maxVal int) (int, error) {
+	return askParsed(ctx, label, "", parseInt(minVal, maxVal))
+}
+
+func AskInt(label string, minVal, maxVal int) (int, error) {
+	return New().AskInt(label, minVal, maxVal)
+}
+
+// AskIntDefault is the same as AskInt but will return dflt if the input is empty
+func (ctx *Ctx) AskIntDefault(label string, minVal, maxVal, dflt int) (int, error) {
+	return askParsed(ctx, label, strconv.Itoa(dflt), parseInt(minVal, maxVal))
+}
+
+func AskIntDefault(label string, minVal, maxVal, dflt int) (int, error) {
+	return New().AskIntDefault(label, minVal, maxVal, dflt)
+}
+
+// AskFloat will prompt the user for a number between minVal and maxVal
+// inclusive, re-prompting until a valid number is given.
+func (ctx *Ctx) AskFloat(label string, minVal, maxVal float64) (float64, error) {
+	return askParsed(ctx, label, "", parseFloat(minVal, maxVal))
+}
+
+func AskFloat(label string, minVal, maxVal float64) (float64, error) {
+	return New().AskFloat(label, minVal, maxVal)
+}
+
+// AskFloatDefault is the same as AskFloat but will return dflt if the input is
+// empty
+func (ctx *Ctx) AskFloatDefault(label string, minVal, maxVal, dflt float64) (float64, error) {
+	return askParsed(ctx, label, strconv.FormatFloat(dflt, 'f', -1, 64), parseFloat(minVal, maxVal))
+}
+
+func AskFloatDefault(label string, minVal, maxVal, dflt float64) (float64, error) {
+	return New().AskFloatDefault(label, minVal, maxVal, dflt)
+}
+
+// AskDuration will prompt the user for a duration like 1h30m between minVal and
+// maxVal inclusive, re-prompting until a valid duration is given.
+func (ctx *Ctx) AskDuration(label string, minVal, maxVal time.Duration) (time.Duration, error) {
+	return askParsed(ctx, label, "", parseDuration(minVal, maxVal))
+}
+
+func AskDuration(label string, minVal, maxVal time.Duration) (time.Duration, error) {
+	return New().AskDuration(label, minVal, maxVal)
+}
+
+// AskDurationDefault is the same as AskDuration but will return dflt if the
+// input is empty
+func (ctx *Ctx) AskDurationDefault(label string, minVal, maxVal, dflt time.Duration) (time.Duration, error) {
+	return askParsed(ctx, label, dflt.String(), parseDuration(minVal, maxVal))
+}
+
+func AskDurationDefault(label string, minVal, maxVal, dflt time.Duration) (time.Duration, error) {
+	return New().AskDurationDefault(label, minVal, maxVal, dflt)
+}
+
+// AskDate will prompt the user for a date in the format of layout, between
+// minVal and maxVal inclusive. A zero minVal or maxVal leaves that side
+// unbounded.
+func (ctx *Ctx) AskDate(label, layout string, minVal, maxVal time.Time) (time.Time, error) {
+	return askParsed(ctx, label, "", parseDate(layout, minVal, maxVal))
+}
+
+func AskDate(label, layout string, minVal, maxVal time.Time) (time.Time, error) {
+	return New().AskDate(label, layout, minVal, maxVal)
+}
+
+// AskDateDefault is the same as AskDate but will return dflt if the input is
+// empty
+func (ctx *Ctx) AskDateDefault(label, layout string, minVal, maxVal, dflt time.Time) (time.Time, error) {
+	return askParsed(ctx, label, dflt.Format(layout), parseDate(layout, minVal, maxVal))
+}
+
+func AskDateDefault(label, layout string, minVal, maxVal, dflt time.Time) (time.Time, error) {
+	return New().AskDateDefault(label, layout, minVal, maxVal, dflt)
+}
+
+// askParsed asks until the input can be parsed, showing the parse error inline
+func askParsed[T any](ctx *Ctx, label, what string, parse func(string) (T, error)) (T, error) {
+	var val T
+	_, err := ctx.askValid(label, what, func(input string) (perr error) {
+		val, perr = parse(input)
+		return perr
+	})
+	return val, err
+}
+
+func parseInt(minVal, maxVal int) func(string) (int, error) {
+	return func(input string) (int, error) {
+		val, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil {
+			return 0, errors.New("must be an integer")
+		}
+		return val, inRange(val, minVal, maxVal)
+	}
+}
+
+func parseFloat(minVal, maxVal float64) func(string) (float64, error) {
+	return func(input string) (float64, error) {
+		val, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		if err != nil {
+			return 0, errors.New("must be a number")
+		}
+		return val, inRange(val, minVal, maxVal)
+	}
+}
+
+func parseDuration(minVal, maxVal time.Duration) func(string) (time.Duration, error) {
+	return func(input string) (time.Duration, error) {
+		val, err := time.ParseDuration(strings.TrimSpace(input))
+		if err != nil {
+			return 0, errors.New("must be a duration like 1h30m")
+		}
+		return val, inRange(val, minVal, maxVal)
+	}
+}
+
+func parseDate(layout string, minVal, maxVal time.Time) func(string) (time.Time, error) {
+	return func(input string) (time.Time, error) {
+		val, err := time.Parse(layout, strings.TrimSpace(input))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("must be a date like %v", layout)
+		} else if !minVal.IsZero() && val.Before(minVal) {
+			return val, fmt.Errorf("must be on or after %v", minVal.Format(layout))
+		} else if !maxVal.IsZero() && val.After(maxVal) {
+			return val, fmt.Errorf("must be on or before %v", maxVal.Format(layout))
+		}
+		return val, nil
+	}
+}
+
+func inRange[T cmp.Ordered](val, minVal, maxVal T) error {
+	if val < minVal || val > maxVal {
+		return fmt.Errorf("must be between %v and %v", minVal, maxVal)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package gluey_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestAskInt(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("1", "2", gluetest.KeyEnter, "5", gluetest.KeyEnter)
+	if val, err := term.Ctx().AskInt("Count?", 1, 10); err != nil || val != 5 {
+		t.Errorf("AskInt = %v, %v", val, err)
+	}
+}
+
+func TestAskIntDefault(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type(gluetest.KeyEnter)
+	if val, err := term.Ctx().AskIntDefault("Count?", 1, 10, 3); err != nil || val != 3 {
+		t.Errorf("AskIntDefault = %v, %v", val, err)
+	}
+}
+
+func TestAskFloat(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("x", gluetest.KeyEnter, "0", ".", "5", gluetest.KeyEnter)
+	if val, err := term.Ctx().AskFloat("Ratio?", 0, 1); err != nil || val != 0.5 {
+		t.Errorf("AskFloat = %v, %v", val, err)
+	}
+}
+
+func TestAskDuration(t *testing.T) {
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Wait?": "1h30m"}))
+	if val, err := ctx.AskDuration("Wait?", 0, 2*time.Hour); err != nil || val != 90*time.Minute {
+		t.Errorf("AskDuration = %v, %v", val, err)
+	}
+	if _, err := ctx.AskDuration("Wait?", 0, time.Hour); err == nil {
+		t.Error("AskDuration accepted a duration out of range")
+	}
+}
+
+func TestAskDate(t *testing.T) {
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"When?": "2024-02-29"}))
+	val, err := ctx.AskDate("When?", time.DateOnly, time.Time{}, time.Time{})
+	if err != nil || !val.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("AskDate = %v, %v", val, err)
+	}
+	minVal := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := ctx.AskDate("When?", time.DateOnly, minVal, time.Time{}); err == nil {
+		t.Error("AskDate accepted a date before the minimum")
+	}
+}
@@ -0,0 +1,69 @@
+package gluey
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Validator checks the input of a prompt, returning an error describing why the
+// input is invalid.
+type Validator func(input string) error
+
+// All combines validators, returning the error of the first one that fails
+func All(validators ...Validator) Validator {
+	return func(input string) error {
+		for _, validate := range validators {
+			if err := validate(input); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// NotEmpty requires the input to contain something other than whitespace
+func NotEmpty(input string) error {
+	if strings.TrimSpace(input) == "" {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+// IntRange requires the input to be an integer between minVal and maxVal inclusive
+func IntRange(minVal, maxVal int) Validator {
+	return func(input string) error {
+		_, err := parseInt(minVal, maxVal)(input)
+		return err
+	}
+}
+
+// Match requires the input to match the regular expression
+func Match(re *regexp.Regexp) Validator {
+	return func(input string) error {
+		if !re.MatchString(input) {
+			return fmt.Errorf("must match %v", re)
+		}
+		return nil
+	}
+}
+
+// URL requires the input to be an absolute URL with a scheme and host
+func URL(input string) error {
+	u, err := url.ParseRequestURI(strings.TrimSpace(input))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New("must be a valid URL")
+	}
+	return nil
+}
+
+// PathExists requires the input to be a path to an existing file or directory
+func PathExists(input string) error {
+	if _, err := os.Stat(input); err != nil {
+		return fmt.Errorf("%v does not exist", input)
+	}
+	return nil
+}
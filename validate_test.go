@@ -0,0 +1,63 @@
+package gluey_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/tanema/gluey"
+	"github.com/tanema/gluey/gluetest"
+)
+
+func TestValidators(t *testing.T) {
+	cases := []struct {
+		name     string
+		validate gluey.Validator
+		input    string
+		valid    bool
+	}{
+		{"NotEmpty", gluey.NotEmpty, "a", true},
+		{"NotEmpty blank", gluey.NotEmpty, "  ", false},
+		{"IntRange", gluey.IntRange(1, 3), "2", true},
+		{"IntRange out of range", gluey.IntRange(1, 3), "4", false},
+		{"IntRange not a number", gluey.IntRange(1, 3), "two", false},
+		{"Match", gluey.Match(regexp.MustCompile(`^v\d+$`)), "v1", true},
+		{"Match fails", gluey.Match(regexp.MustCompile(`^v\d+$`)), "1", false},
+		{"URL", gluey.URL, "https://example.com/path", true},
+		{"URL without host", gluey.URL, "example", false},
+		{"PathExists", gluey.PathExists, ".", true},
+		{"PathExists missing", gluey.PathExists, "does/not/exist", false},
+		{"All", gluey.All(gluey.NotEmpty, gluey.IntRange(0, 9)), "5", true},
+		{"All fails", gluey.All(gluey.NotEmpty, gluey.IntRange(0, 9)), "", false},
+	}
+	for _, c := range cases {
+		if err := c.validate(c.input); (err == nil) != c.valid {
+			t.Errorf("%v(%q) = %v", c.name, c.input, err)
+		}
+	}
+}
+
+func TestAskValidated(t *testing.T) {
+	term := gluetest.NewTerminal()
+	term.Type("x", gluetest.KeyEnter)
+	done := make(chan string)
+	go func() {
+		input, _ := term.Ctx().AskValidated("Version?", gluey.Match(regexp.MustCompile(`^v\d+$`)))
+		done <- input
+	}()
+	waitFor(t, term, "must match")
+	term.Type("v", "2", gluetest.KeyEnter)
+	if input := <-done; input != "v2" {
+		t.Errorf("AskValidated = %q", input)
+	}
+	if got, want := term.Screen(), "? Version?\n> v2"; got != want {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestAskValidatedNonInteractive(t *testing.T) {
+	ctx := gluetest.NewTerminal().Ctx(gluey.NonInteractive(gluey.AnswerMap{"Count?": "ten"}))
+	if _, err := ctx.AskValidated("Count?", gluey.IntRange(1, 10)); err == nil || !strings.Contains(err.Error(), "must be an integer") {
+		t.Errorf("AskValidated = %v", err)
+	}
+}